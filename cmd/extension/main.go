@@ -14,7 +14,9 @@ import (
 	"gopkg.in/yaml.v2"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	"github.com/palestamp/ksql/pkg/cache"
 	"github.com/palestamp/ksql/pkg/kubeapi"
+	"github.com/palestamp/ksql/pkg/redact"
 	"github.com/palestamp/ksql/pkg/tables"
 )
 
@@ -25,8 +27,10 @@ var (
 )
 
 type EnvVars struct {
-	LogLevel string `envconfig:"KSQL_LOG_LEVEL"`
-	Config   string `envconfig:"KSQL_CONFIG" default:"config.yaml"`
+	LogLevel    string `envconfig:"KSQL_LOG_LEVEL"`
+	Config      string `envconfig:"KSQL_CONFIG" default:"config.yaml"`
+	Concurrency int    `envconfig:"KSQL_CONCURRENCY"`
+	RedactKey   string `envconfig:"KSQL_REDACTION_KEY"`
 }
 
 func setupLogger(envLvl string) {
@@ -85,13 +89,67 @@ func main() {
 		server.RegisterPlugin(NewPlugin(name, dm))
 	}
 
-	kc := kubeapi.NewKubeConfig(c.IgnoreContexts)
+	concurrency := c.Concurrency
+	if ev.Concurrency > 0 {
+		concurrency = ev.Concurrency
+	}
+
+	kc := kubeapi.NewKubeConfig(
+		c.IgnoreContexts,
+		kubeapi.WithConcurrency(concurrency),
+		kubeapi.WithCache(c.Cache.TTL, c.Cache.MaxEntries),
+		kubeapi.WithRequestTimeout(c.RequestTimeout),
+		kubeapi.WithContextTimeout(c.ContextTimeout),
+		kubeapi.WithPageSize(c.PageSize),
+		kubeapi.WithFailFast(c.FailFast),
+	)
+	redactKey := c.Redaction.Key
+	if ev.RedactKey != "" {
+		redactKey = ev.RedactKey
+	}
+
+	if redactKey == "" && !c.Redaction.Plaintext {
+		log.Warn("Redaction key is not set, hashes of secret values are stable only until restart")
+	}
+
+	r, err := redact.New(redactKey, c.Redaction.Plaintext)
+	if err != nil {
+		log.Fatalf("Error creating redactor: %s\n", err)
+	}
+
+	// deadline applies to tables querying clusters, tables reading kubeconfig
+	// files, cache and error log are not limited
+	plugin := func(name string, tbl tables.Table) *table.Plugin {
+		return NewPlugin(name, tables.WithQueryTimeout(tbl, c.QueryTimeout))
+	}
+
 	server.RegisterPlugin(
 		NewPlugin("k8s_contexts", tables.NewContexts(kc)),
-		NewPlugin("k8s_namespaces", tables.NewNamespaces(kc)),
-		NewPlugin("k8s_containers", tables.NewContainers(kc)),
-		NewPlugin("k8s_env_vars", tables.NewEnvVars(kc)),
-		NewPlugin("k8s_secrets", tables.NewSecrets(kc)),
+		NewPlugin("k8s_kube_clusters", tables.NewKubeClusters(kc)),
+		NewPlugin("k8s_kube_users", tables.NewKubeUsers(kc)),
+		plugin("k8s_namespaces", tables.NewNamespaces(kc)),
+		plugin("k8s_nodes", tables.NewNodes(kc)),
+		plugin("k8s_events", tables.NewEvents(kc)),
+		plugin("k8s_workloads", tables.NewWorkloads(kc)),
+		plugin("k8s_containers", tables.NewContainers(kc)),
+		plugin("k8s_container_resources", tables.NewContainerResources(kc)),
+		plugin("k8s_container_ports", tables.NewContainerPorts(kc)),
+		plugin("k8s_container_probes", tables.NewContainerProbes(kc)),
+		plugin("k8s_volumes", tables.NewVolumes(kc)),
+		plugin("k8s_volume_mounts", tables.NewVolumeMounts(kc)),
+		plugin("k8s_labels", tables.NewLabels(kc)),
+		plugin("k8s_annotations", tables.NewAnnotations(kc)),
+		plugin("k8s_env_vars", tables.NewEnvVars(kc, r)),
+		plugin("k8s_secrets", tables.NewSecrets(kc, r)),
+		plugin("k8s_configmaps", tables.NewConfigMaps(kc)),
+		plugin("k8s_services", tables.NewServices(kc)),
+		plugin("k8s_service_ports", tables.NewServicePorts(kc)),
+		plugin("k8s_ingresses", tables.NewIngresses(kc)),
+		plugin("k8s_pods", tables.NewPods(kc)),
+		plugin("k8s_container_statuses", tables.NewContainerStatuses(kc)),
+		NewPlugin("k8s_cache", tables.NewCache(kc)),
+		NewPlugin("k8s_cache_flush", tables.NewCacheFlush(kc)),
+		NewPlugin("k8s_errors", tables.NewErrors(kc)),
 	)
 
 	log.Info("Starting server")
@@ -107,6 +165,24 @@ func NewPlugin(name string, tbl tables.Table) *table.Plugin {
 type Config struct {
 	Mappings       map[string][]map[string]interface{} `yaml:"mappings"`
 	IgnoreContexts []string                            `yaml:"ignore-contexts"`
+	Concurrency    int                                 `yaml:"concurrency"`
+	Cache          CacheConfig                         `yaml:"cache"`
+	RequestTimeout time.Duration                       `yaml:"request-timeout"`
+	ContextTimeout time.Duration                       `yaml:"context-timeout"`
+	QueryTimeout   time.Duration                       `yaml:"query-timeout"`
+	PageSize       int64                               `yaml:"page-size"`
+	FailFast       bool                                `yaml:"fail-fast"`
+	Redaction      RedactionConfig                     `yaml:"redaction"`
+}
+
+type RedactionConfig struct {
+	Key       string `yaml:"key"`
+	Plaintext bool   `yaml:"plaintext"`
+}
+
+type CacheConfig struct {
+	TTL        time.Duration `yaml:"ttl"`
+	MaxEntries int           `yaml:"max-entries"`
 }
 
 func Load(configFilepath string) (Config, error) {
@@ -115,7 +191,12 @@ func Load(configFilepath string) (Config, error) {
 		return Config{}, fmt.Errorf("unable to load macro manifest file='%s': %v", configFilepath, err)
 	}
 
-	var c Config
+	c := Config{
+		Cache: CacheConfig{
+			TTL:        cache.DefaultTTL,
+			MaxEntries: cache.DefaultMaxEntries,
+		},
+	}
 	err = yaml.Unmarshal(b, &c)
 	return c, err
 }
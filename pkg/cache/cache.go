@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"container/list"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTTL        = 5 * time.Minute
+	DefaultMaxEntries = 4096
+)
+
+// Key identifies cached API response.
+// Namespace is empty for cluster scoped resources,
+// Selector is set for responses filtered server-side.
+type Key struct {
+	Resource  string
+	Context   string
+	Namespace string
+	Selector  string
+}
+
+type Entry struct {
+	Key       Key
+	Items     int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type entry struct {
+	Entry
+	value interface{}
+}
+
+// Cache is a thread-safe LRU cache with per-entry expiration.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	lru        *list.List
+	entries    map[Key]*list.Element
+
+	now func() time.Time
+}
+
+// New creates cache, ttl <= 0 disables expiration, maxEntries <= 0 disables size bound.
+func New(ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[Key]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (c *Cache) Get(key Key) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry)
+	if c.expired(e) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	return e.value, true
+}
+
+func (c *Cache) Set(key Key, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	e := &entry{
+		Entry: Entry{
+			Key:       key,
+			Items:     itemsCount(value),
+			CreatedAt: now,
+		},
+		value: value,
+	}
+
+	if c.ttl > 0 {
+		e.ExpiresAt = now.Add(c.ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(e)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Flush removes all entries matched by fn and returns them.
+func (c *Cache) Flush(fn func(Key) bool) []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []Entry
+	for key, el := range c.entries {
+		if fn(key) {
+			out = append(out, el.Value.(*entry).Entry)
+			c.remove(el)
+		}
+	}
+
+	sortEntries(out)
+
+	return out
+}
+
+// Entries returns all non-expired entries.
+func (c *Cache) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var out []Entry
+	for _, el := range c.entries {
+		e := el.Value.(*entry)
+		if c.expired(e) {
+			c.remove(el)
+			continue
+		}
+
+		out = append(out, e.Entry)
+	}
+
+	sortEntries(out)
+
+	return out
+}
+
+func (c *Cache) expired(e *entry) bool {
+	return !e.ExpiresAt.IsZero() && !c.now().Before(e.ExpiresAt)
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*entry).Key)
+}
+
+func itemsCount(v interface{}) int {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len()
+	}
+
+	return 1
+}
+
+func sortEntries(es []Entry) {
+	sort.Slice(es, func(i, j int) bool {
+		a, b := es[i].Key, es[j].Key
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Selector < b.Selector
+	})
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache_expiration(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	c := New(time.Minute, 0)
+	c.now = func() time.Time { return now }
+
+	key := Key{Resource: "pods", Context: "a", Namespace: "default"}
+	c.Set(key, []string{"p1"})
+
+	if _, ok := c.Get(key); !ok {
+		t.Fatal("expected cache hit")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get(key); ok {
+		t.Fatal("expected entry to expire")
+	}
+}
+
+func TestCache_maxEntries(t *testing.T) {
+	c := New(0, 2)
+
+	a := Key{Resource: "pods", Context: "a"}
+	b := Key{Resource: "pods", Context: "b"}
+	d := Key{Resource: "pods", Context: "d"}
+
+	c.Set(a, 1)
+	c.Set(b, 2)
+	c.Get(a)
+	c.Set(d, 3)
+
+	if _, ok := c.Get(b); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.Get(a); !ok {
+		t.Fatal("expected recently used entry to stay")
+	}
+}
+
+func TestCache_Flush(t *testing.T) {
+	c := New(0, 0)
+
+	c.Set(Key{Resource: "pods", Context: "a", Namespace: "n1"}, []int{1, 2})
+	c.Set(Key{Resource: "pods", Context: "b", Namespace: "n1"}, []int{1})
+	c.Set(Key{Resource: "secrets", Context: "a", Namespace: "n1"}, []int{})
+
+	flushed := c.Flush(func(k Key) bool { return k.Context == "a" })
+
+	var got []Key
+	for _, e := range flushed {
+		got = append(got, e.Key)
+	}
+
+	expected := []Key{
+		{Resource: "pods", Context: "a", Namespace: "n1"},
+		{Resource: "secrets", Context: "a", Namespace: "n1"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+
+	if n := len(c.Entries()); n != 1 {
+		t.Fatalf("expected=1; got=%d", n)
+	}
+}
@@ -1,46 +1,169 @@
 package kubeapi
 
 import (
+	"context"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/palestamp/ksql/pkg/cache"
 	"github.com/palestamp/ksql/pkg/kubeconfig"
 )
 
-func NewKubeConfig(ignoredContexts []string) *KubeConfig {
+const (
+	DefaultConcurrency    = 8
+	DefaultRequestTimeout = 30 * time.Second
+	DefaultContextTimeout = 2 * time.Minute
+)
+
+type Option func(*KubeConfig)
+
+// WithConcurrency limits number of parallel API calls issued by a single query.
+func WithConcurrency(n int) Option {
+	return func(c *KubeConfig) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithCache configures freshness and size bound of API responses cache.
+func WithCache(ttl time.Duration, maxEntries int) Option {
+	return func(c *KubeConfig) {
+		c.cache = cache.New(ttl, maxEntries)
+	}
+}
+
+// WithRequestTimeout limits duration of a single API request.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *KubeConfig) {
+		if d > 0 {
+			c.requestTimeout = d
+		}
+	}
+}
+
+// WithContextTimeout limits duration of a single list call to a context,
+// including all its pages, so slow clusters do not stall the whole query.
+func WithContextTimeout(d time.Duration) Option {
+	return func(c *KubeConfig) {
+		if d > 0 {
+			c.contextTimeout = d
+		}
+	}
+}
+
+// WithPageSize limits number of objects returned by a single list request,
+// larger collections are fetched in several requests.
+func WithPageSize(n int64) Option {
+	return func(c *KubeConfig) {
+		if n > 0 {
+			c.pageSize = n
+		}
+	}
+}
+
+// WithFailFast makes any failed API request fail the whole query,
+// by default failed contexts and namespaces are skipped.
+func WithFailFast(failFast bool) Option {
+	return func(c *KubeConfig) {
+		c.failFast = failFast
+	}
+}
+
+func NewKubeConfig(ignoredContexts []string, opts ...Option) *KubeConfig {
 	ignore := make(map[string]struct{})
 	for _, k := range ignoredContexts {
 		ignore[k] = struct{}{}
 	}
 
-	return &KubeConfig{ignoredContexts: ignore}
+	c := &KubeConfig{
+		ignoredContexts: ignore,
+		concurrency:     DefaultConcurrency,
+		requestTimeout:  DefaultRequestTimeout,
+		contextTimeout:  DefaultContextTimeout,
+		pageSize:        DefaultPageSize,
+		cache:           cache.New(cache.DefaultTTL, cache.DefaultMaxEntries),
+		clientsets:      make(map[string]kubernetes.Interface),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 type KubeConfig struct {
 	ignoredContexts map[string]struct{}
+	concurrency     int
+	requestTimeout  time.Duration
+	contextTimeout  time.Duration
+	pageSize        int64
+	failFast        bool
+	cache           *cache.Cache
+	requests        singleflight.Group
+	clientsetsMu    sync.Mutex
+	clientsets      map[string]kubernetes.Interface
+	generationMu    sync.Mutex
+	generation      uint64
+	errors          errorLog
+}
+
+func (c *KubeConfig) Concurrency() int {
+	return c.concurrency
+}
+
+func (c *KubeConfig) FailFast() bool {
+	return c.failFast
+}
+
+// Errors returns recently failed API requests.
+func (c *KubeConfig) Errors() []RequestError {
+	return c.errors.list()
 }
 
 func (c *KubeConfig) ListContexts() ([]string, error) {
-	kc := kubeconfig.New(kubeconfig.DefaultLoader)
-	defer kc.Close()
+	ctxs, err := c.ListContextDetails()
+	if err != nil {
+		return nil, err
+	}
 
-	if err := kc.Parse(); err != nil {
+	nc := make([]string, 0, len(ctxs))
+	for _, ctx := range ctxs {
+		nc = append(nc, ctx.Name)
+	}
+
+	return nc, nil
+}
+
+// ListContextDetails returns not ignored contexts sorted by name.
+func (c *KubeConfig) ListContextDetails() ([]kubeconfig.Context, error) {
+	kc, err := parseKubeconfig()
+	if err != nil {
 		return nil, err
 	}
+	defer kc.Close()
 
-	ctxs := kc.ContextNames()
-	sort.Strings(ctxs)
+	ctxs := kc.Contexts()
+	sort.Slice(ctxs, func(i, j int) bool { return ctxs[i].Name < ctxs[j].Name })
 
-	nc := make([]string, 0)
+	nc := make([]kubeconfig.Context, 0)
 	for _, ctx := range ctxs {
-		if _, ok := c.ignoredContexts[ctx]; ok {
+		if _, ok := c.ignoredContexts[ctx.Name]; ok {
 			continue
 		}
 
@@ -50,152 +173,457 @@ func (c *KubeConfig) ListContexts() ([]string, error) {
 	return nc, nil
 }
 
-var namespacesCache = make(map[string][]string)
+func (c *KubeConfig) ListClusters() ([]kubeconfig.Cluster, error) {
+	kc, err := parseKubeconfig()
+	if err != nil {
+		return nil, err
+	}
+	defer kc.Close()
 
-func (c *KubeConfig) ListNamespaces(context string) ([]string, error) {
-	logger := log.
-		WithField("resource", "namespaces").
-		WithField("context", context)
+	return kc.Clusters(), nil
+}
 
-	if ns, ok := namespacesCache[context]; ok {
-		logger.Info("Cache hit")
-		return ns, nil
+func (c *KubeConfig) ListUsers() ([]kubeconfig.User, error) {
+	kc, err := parseKubeconfig()
+	if err != nil {
+		return nil, err
 	}
+	defer kc.Close()
 
-	cs, err := c.getClientset(context)
-	if err != nil {
+	return kc.Users(), nil
+}
+
+func parseKubeconfig() (*kubeconfig.Kubeconfig, error) {
+	kc := kubeconfig.New(kubeconfig.DefaultLoader)
+	if err := kc.Parse(); err != nil {
+		kc.Close()
 		return nil, err
 	}
 
-	logger.Info("Requesting API")
-	resp, err := cs.CoreV1().Namespaces().List(metav1.ListOptions{})
+	return kc, nil
+}
+
+func (c *KubeConfig) ListNamespaces(ctx context.Context, k8sContext string) ([]string, error) {
+	items, err := c.ListNamespaceObjects(ctx, k8sContext)
 	if err != nil {
 		return nil, err
 	}
 
-	namespaces := make([]string, 0)
-	for _, namespace := range resp.Items {
+	namespaces := make([]string, 0, len(items))
+	for _, namespace := range items {
 		namespaces = append(namespaces, namespace.GetName())
 	}
 
-	namespacesCache[context] = namespaces
-
 	return namespaces, nil
 }
 
-type k struct {
-	c string
-	n string
+func (c *KubeConfig) ListNamespaceObjects(ctx context.Context, k8sContext string) ([]corev1.Namespace, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "namespaces", Context: k8sContext}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.CoreV1().Namespaces().List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp.Items, resp.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]corev1.Namespace), nil
 }
 
-var deploymentsCache = make(map[k][]appsv1.Deployment)
+func (c *KubeConfig) ListNodes(ctx context.Context, k8sContext string) ([]corev1.Node, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "nodes", Context: k8sContext}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
 
-func (c *KubeConfig) ListDeployments(context, namespace string) ([]appsv1.Deployment, error) {
-	logger := log.
-		WithField("resource", "deployments").
-		WithField("context", context).
-		WithField("namespace", namespace)
+		return c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			nodes, err := cs.CoreV1().Nodes().List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
 
-	key := k{context, namespace}
-	if ds, ok := deploymentsCache[key]; ok {
-		logger.Info("Cache hit")
-		return ds, nil
+			return nodes.Items, nodes.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	cs, err := c.getClientset(context)
+	return v.([]corev1.Node), nil
+}
+
+// ListDeployments lists deployments matching label selector, selectors of
+// this and other namespaced List methods are evaluated by API server and
+// empty selector matches all objects.
+func (c *KubeConfig) ListDeployments(ctx context.Context, k8sContext, namespace, labelSelector string) ([]appsv1.Deployment, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "deployments", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.AppsV1().Deployments(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp.Items, resp.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Info("Requesting API")
-	resp, err := cs.AppsV1().Deployments(namespace).List(metav1.ListOptions{})
+	return v.([]appsv1.Deployment), nil
+}
+
+func (c *KubeConfig) ListStatefulSets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]appsv1.StatefulSet, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "statefulsets", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.AppsV1().StatefulSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp.Items, resp.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	deploymentsCache[key] = resp.Items
-
-	return resp.Items, nil
+	return v.([]appsv1.StatefulSet), nil
 }
 
-var statefulsetCache = make(map[k][]appsv1.StatefulSet)
+func (c *KubeConfig) ListDaemonSets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]appsv1.DaemonSet, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "daemonsets", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
 
-func (c *KubeConfig) ListStatefulSets(context, namespace string) ([]appsv1.StatefulSet, error) {
-	logger := log.
-		WithField("resource", "statefulsets").
-		WithField("context", context).
-		WithField("namespace", namespace)
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.AppsV1().DaemonSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
 
-	key := k{context, namespace}
-	if ds, ok := statefulsetCache[key]; ok {
-		logger.Info("Cache hit")
-		return ds, nil
+			return resp.Items, resp.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	cs, err := c.getClientset(context)
+	return v.([]appsv1.DaemonSet), nil
+}
+
+func (c *KubeConfig) ListReplicaSets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]appsv1.ReplicaSet, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "replicasets", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp.Items, resp.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Info("Requesting API")
-	resp, err := cs.AppsV1().StatefulSets(namespace).List(metav1.ListOptions{})
+	return v.([]appsv1.ReplicaSet), nil
+}
+
+func (c *KubeConfig) ListJobs(ctx context.Context, k8sContext, namespace, labelSelector string) ([]batchv1.Job, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "jobs", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			resp, err := cs.BatchV1().Jobs(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return resp.Items, resp.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	statefulsetCache[key] = resp.Items
+	return v.([]batchv1.Job), nil
+}
+
+func (c *KubeConfig) ListSecrets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]corev1.Secret, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "secrets", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			secrets, err := cs.CoreV1().Secrets(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return secrets.Items, secrets.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return resp.Items, nil
+	return v.([]corev1.Secret), nil
 }
 
-var secretsCache = make(map[k][]corev1.Secret)
+func (c *KubeConfig) ListConfigMaps(ctx context.Context, k8sContext, namespace string) ([]corev1.ConfigMap, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "configmaps", Context: k8sContext, Namespace: namespace}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
 
-func (c *KubeConfig) ListSecrets(k8sContext, namespace string) ([]corev1.Secret, error) {
-	logger := log.
-		WithField("resource", "secrets").
-		WithField("context", k8sContext).
-		WithField("namespace", namespace)
+		return c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			configMaps, err := cs.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
 
-	key := k{k8sContext, namespace}
-	if ds, ok := secretsCache[key]; ok {
-		logger.Info("Cache hit")
-		return ds, nil
+			return configMaps.Items, configMaps.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	cs, err := c.getClientset(k8sContext)
+	return v.([]corev1.ConfigMap), nil
+}
+
+func (c *KubeConfig) ListServices(ctx context.Context, k8sContext, namespace string) ([]corev1.Service, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "services", Context: k8sContext, Namespace: namespace}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			services, err := cs.CoreV1().Services(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return services.Items, services.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	logger.Info("Requesting API")
-	secrets, err := cs.CoreV1().Secrets(namespace).List(metav1.ListOptions{})
+	return v.([]corev1.Service), nil
+}
+
+func (c *KubeConfig) ListPods(ctx context.Context, k8sContext, namespace, labelSelector string) ([]corev1.Pod, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "pods", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			pods, err := cs.CoreV1().Pods(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return pods.Items, pods.Continue, nil
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	secretsCache[key] = secrets.Items
+	return v.([]corev1.Pod), nil
+}
+
+// CacheEntries returns currently cached API responses.
+func (c *KubeConfig) CacheEntries() []cache.Entry {
+	return c.cache.Entries()
+}
+
+// FlushCache drops cached API responses and clientsets matched by fn,
+// clientsets are matched by key with "clientset" resource and are not
+// reported in returned entries. Responses of requests in flight during the
+// flush are not cached.
+func (c *KubeConfig) FlushCache(fn func(cache.Key) bool) []cache.Entry {
+	c.clientsetsMu.Lock()
+	for k8sContext := range c.clientsets {
+		if fn(cache.Key{Resource: clientsetResource, Context: k8sContext}) {
+			delete(c.clientsets, k8sContext)
+		}
+	}
+	c.clientsetsMu.Unlock()
+
+	c.generationMu.Lock()
+	defer c.generationMu.Unlock()
 
-	return secrets.Items, nil
+	c.generation++
+
+	return c.cache.Flush(fn)
+}
+
+func (c *KubeConfig) cacheGeneration() uint64 {
+	c.generationMu.Lock()
+	defer c.generationMu.Unlock()
+
+	return c.generation
+}
+
+// cacheSet stores response unless cache was flushed since generation.
+func (c *KubeConfig) cacheSet(generation uint64, key cache.Key, v interface{}) {
+	c.generationMu.Lock()
+	defer c.generationMu.Unlock()
+
+	if c.generation == generation {
+		c.cache.Set(key, v)
+	}
 }
 
-var clientsetCache = make(map[string]*kubernetes.Clientset)
+// cached returns cached response or calls fetch. Concurrent misses of the
+// same key share a single fetch, which is not bound to any of the callers
+// and is limited by c.contextTimeout only, so cancelled query does not fail
+// the others waiting for the same response. Each caller stops waiting when
+// its ctx is done, such requests are not recorded as errors of the context.
+func (c *KubeConfig) cached(ctx context.Context, key cache.Key, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	logger := log.
+		WithField("resource", key.Resource).
+		WithField("context", key.Context)
+
+	if key.Namespace != "" {
+		logger = logger.WithField("namespace", key.Namespace)
+	}
 
-func (kc *KubeConfig) getClientset(context string) (*kubernetes.Clientset, error) {
-	if cs, ok := clientsetCache[context]; ok {
+	if key.Selector != "" {
+		logger = logger.WithField("selector", key.Selector)
+	}
+
+	if v, ok := c.cache.Get(key); ok {
+		logger.Info("Cache hit")
+		return v, nil
+	}
+
+	// requests started before flush are not shared with the ones started after it
+	generation := c.cacheGeneration()
+
+	ch := c.requests.DoChan(requestKey(generation, key), func() (interface{}, error) {
+		// response might have been stored by a request finished after Get above
+		if v, ok := c.cache.Get(key); ok {
+			return v, nil
+		}
+
+		fetchCtx := context.Background()
+		if c.contextTimeout > 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(fetchCtx, c.contextTimeout)
+			defer cancel()
+		}
+
+		logger.Info("Requesting API")
+		v, err := fetch(fetchCtx)
+		if err != nil {
+			logger.WithError(err).Error("Request failed")
+			c.errors.record(key, err)
+
+			return nil, err
+		}
+
+		c.cacheSet(generation, key, v)
+
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "failed to list %s in context %q", key.Resource, key.Context)
+	case res := <-ch:
+		if res.Shared {
+			logger.Debug("Shared in-flight request")
+		}
+
+		if res.Err != nil {
+			return nil, errors.Wrapf(res.Err, "failed to list %s in context %q", key.Resource, key.Context)
+		}
+
+		return res.Val, nil
+	}
+}
+
+func requestKey(generation uint64, key cache.Key) string {
+	return strings.Join([]string{strconv.FormatUint(generation, 10), key.Resource, key.Context, key.Namespace, key.Selector}, "\x00")
+}
+
+const clientsetResource = "clientset"
+
+// getClientset returns clientset of context, clientsets are kept apart from
+// API responses, so they are not expired or evicted with them.
+func (kc *KubeConfig) getClientset(k8sContext string) (kubernetes.Interface, error) {
+	kc.clientsetsMu.Lock()
+	defer kc.clientsetsMu.Unlock()
+
+	if cs, ok := kc.clientsets[k8sContext]; ok {
 		return cs, nil
 	}
 
+	// use the same files as ListContexts, so contexts defined in several
+	// files resolve to the same entries
+	paths, err := kubeconfig.Paths()
+	if err != nil {
+		return nil, err
+	}
+
 	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{CurrentContext: context},
+		&clientcmd.ClientConfigLoadingRules{Precedence: paths},
+		&clientcmd.ConfigOverrides{CurrentContext: k8sContext},
 	).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config.Timeout = kc.requestTimeout
 
 	cs, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	clientsetCache[context] = cs
+	kc.clientsets[k8sContext] = cs
 
 	return cs, nil
 }
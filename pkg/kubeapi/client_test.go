@@ -0,0 +1,138 @@
+package kubeapi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/palestamp/ksql/pkg/cache"
+)
+
+func TestCachedSharesConcurrentMisses(t *testing.T) {
+	c := NewKubeConfig(nil)
+	key := cache.Key{Resource: "pods", Context: "c1", Namespace: "default"}
+
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return []string{"a"}, nil
+	}
+
+	const callers = 8
+
+	var started, done sync.WaitGroup
+	started.Add(callers)
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer done.Done()
+			started.Done()
+
+			if _, err := c.cached(context.Background(), key, fetch); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	started.Wait()
+	close(release)
+	done.Wait()
+
+	// callers arriving after the shared fetch finished are served from cache
+	if fetches != 1 {
+		t.Errorf("expected=1 fetch; got=%d", fetches)
+	}
+}
+
+func TestCachedCancelledCallerDoesNotFailOthers(t *testing.T) {
+	c := NewKubeConfig(nil)
+	key := cache.Key{Resource: "pods", Context: "c1", Namespace: "default"}
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		close(fetching)
+		select {
+		case <-release:
+			return []string{"a"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		_, err := c.cached(ctx, key, fetch)
+		cancelled <- err
+	}()
+	<-fetching
+
+	live := make(chan error)
+	go func() {
+		_, err := c.cached(context.Background(), key, fetch)
+		live <- err
+	}()
+
+	cancel()
+	if err := <-cancelled; errors.Cause(err) != context.Canceled {
+		t.Errorf("expected cancelled caller to fail with %v; got=%v", context.Canceled, err)
+	}
+
+	close(release)
+	if err := <-live; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if errs := c.Errors(); len(errs) != 0 {
+		t.Errorf("expected no recorded errors; got=%v", errs)
+	}
+}
+
+func TestCachedFlushDuringFetch(t *testing.T) {
+	c := NewKubeConfig(nil)
+	key := cache.Key{Resource: "pods", Context: "c1", Namespace: "default"}
+
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		close(fetching)
+		<-release
+		return []string{"stale"}, nil
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.cached(context.Background(), key, fetch)
+		done <- err
+	}()
+	<-fetching
+
+	c.FlushCache(func(cache.Key) bool { return true })
+
+	// request started after flush does not join the one started before it
+	v, err := c.cached(context.Background(), key, func(ctx context.Context) (interface{}, error) {
+		return []string{"fresh"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := v.([]string)[0]; got != "fresh" {
+		t.Errorf("expected=fresh; got=%s", got)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if v, ok := c.cache.Get(key); !ok || v.([]string)[0] != "fresh" {
+		t.Errorf("expected cached=fresh; got=%v", v)
+	}
+}
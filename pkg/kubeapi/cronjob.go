@@ -0,0 +1,88 @@
+package kubeapi
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/palestamp/ksql/pkg/cache"
+)
+
+// ListCronJobs lists batch/v1 cron jobs, clusters older than 1.21 are queried
+// through batch/v1beta1 and results are converted to v1.
+func (c *KubeConfig) ListCronJobs(ctx context.Context, k8sContext, namespace, labelSelector string) ([]batchv1.CronJob, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "cronjobs", Context: k8sContext, Namespace: namespace, Selector: labelSelector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			cronJobs, err := cs.BatchV1().CronJobs(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return cronJobs.Items, cronJobs.Continue, nil
+		})
+		if err == nil {
+			return items, nil
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		v, err := c.paginate(metav1.ListOptions{LabelSelector: labelSelector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			legacy, err := cs.BatchV1beta1().CronJobs(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return legacy.Items, legacy.Continue, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		legacy := v.([]batchv1beta1.CronJob)
+
+		out := make([]batchv1.CronJob, 0, len(legacy))
+		for _, cj := range legacy {
+			out = append(out, convertCronJob(cj))
+		}
+
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]batchv1.CronJob), nil
+}
+
+func convertCronJob(in batchv1beta1.CronJob) batchv1.CronJob {
+	return batchv1.CronJob{
+		ObjectMeta: in.ObjectMeta,
+		Spec: batchv1.CronJobSpec{
+			Schedule:                in.Spec.Schedule,
+			StartingDeadlineSeconds: in.Spec.StartingDeadlineSeconds,
+			ConcurrencyPolicy:       batchv1.ConcurrencyPolicy(in.Spec.ConcurrencyPolicy),
+			Suspend:                 in.Spec.Suspend,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: in.Spec.JobTemplate.ObjectMeta,
+				Spec:       in.Spec.JobTemplate.Spec,
+			},
+			SuccessfulJobsHistoryLimit: in.Spec.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     in.Spec.FailedJobsHistoryLimit,
+		},
+		Status: batchv1.CronJobStatus{
+			Active:             in.Status.Active,
+			LastScheduleTime:   in.Status.LastScheduleTime,
+			LastSuccessfulTime: in.Status.LastSuccessfulTime,
+		},
+	}
+}
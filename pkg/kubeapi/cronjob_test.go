@@ -0,0 +1,82 @@
+package kubeapi
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestListCronJobs(t *testing.T) {
+	cs := fake.NewSimpleClientset(&batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *"},
+	})
+
+	c := NewKubeConfig(nil)
+	c.clientsets["c1"] = cs
+
+	cronJobs, err := c.ListCronJobs(context.Background(), "c1", "default", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cronJobs) != 1 || cronJobs[0].Name != "backup" {
+		t.Errorf("expected backup cron job; got=%+v", cronJobs)
+	}
+}
+
+func TestListCronJobsV1beta1Fallback(t *testing.T) {
+	suspend := true
+	cs := fake.NewSimpleClientset(&batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          "0 * * * *",
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+			Suspend:           &suspend,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "backup"}},
+			},
+		},
+	})
+
+	// clusters older than 1.21 do not serve batch/v1 cron jobs
+	cs.PrependReactor("list", "cronjobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Version != "v1" {
+			return false, nil, nil
+		}
+
+		return true, nil, apierrors.NewNotFound(action.GetResource().GroupResource(), "")
+	})
+
+	c := NewKubeConfig(nil)
+	c.clientsets["c1"] = cs
+
+	cronJobs, err := c.ListCronJobs(context.Background(), "c1", "default", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(cronJobs) != 1 {
+		t.Fatalf("expected 1 cron job; got=%d", len(cronJobs))
+	}
+
+	cj := cronJobs[0]
+	if cj.Name != "backup" || cj.Spec.Schedule != "0 * * * *" {
+		t.Errorf("unexpected cron job: %+v", cj)
+	}
+
+	if cj.Spec.ConcurrencyPolicy != batchv1.ForbidConcurrent || cj.Spec.Suspend == nil || !*cj.Spec.Suspend {
+		t.Errorf("spec is not converted: %+v", cj.Spec)
+	}
+
+	if cj.Spec.JobTemplate.Labels["app"] != "backup" {
+		t.Errorf("job template is not converted: %+v", cj.Spec.JobTemplate)
+	}
+}
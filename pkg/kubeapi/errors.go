@@ -0,0 +1,49 @@
+package kubeapi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/palestamp/ksql/pkg/cache"
+)
+
+const maxRecordedErrors = 1024
+
+// RequestError describes failed API request.
+type RequestError struct {
+	Context   string
+	Resource  string
+	Namespace string
+	Err       error
+	Time      time.Time
+}
+
+// errorLog keeps most recent request errors, oldest entries are dropped first.
+type errorLog struct {
+	mu      sync.Mutex
+	entries []RequestError
+}
+
+func (l *errorLog) record(key cache.Key, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, RequestError{
+		Context:   key.Context,
+		Resource:  key.Resource,
+		Namespace: key.Namespace,
+		Err:       err,
+		Time:      time.Now(),
+	})
+
+	if n := len(l.entries); n > maxRecordedErrors {
+		l.entries = append([]RequestError(nil), l.entries[n-maxRecordedErrors:]...)
+	}
+}
+
+func (l *errorLog) list() []RequestError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]RequestError(nil), l.entries...)
+}
@@ -0,0 +1,60 @@
+package kubeapi
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/palestamp/ksql/pkg/cache"
+)
+
+// EventFilter is translated to field selector, empty fields are not filtered.
+type EventFilter struct {
+	Type               string
+	InvolvedObjectKind string
+	InvolvedObjectName string
+}
+
+func (f EventFilter) selector() string {
+	set := fields.Set{}
+	if f.Type != "" {
+		set["type"] = f.Type
+	}
+	if f.InvolvedObjectKind != "" {
+		set["involvedObject.kind"] = f.InvolvedObjectKind
+	}
+	if f.InvolvedObjectName != "" {
+		set["involvedObject.name"] = f.InvolvedObjectName
+	}
+
+	return fields.SelectorFromSet(set).String()
+}
+
+// ListEvents lists events matching filter, empty namespace lists events
+// across all namespaces.
+func (c *KubeConfig) ListEvents(ctx context.Context, k8sContext, namespace string, filter EventFilter) ([]corev1.Event, error) {
+	selector := filter.selector()
+
+	v, err := c.cached(ctx, cache.Key{Resource: "events", Context: k8sContext, Namespace: namespace, Selector: selector}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.paginate(metav1.ListOptions{FieldSelector: selector}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			events, err := cs.CoreV1().Events(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return events.Items, events.Continue, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]corev1.Event), nil
+}
@@ -0,0 +1,136 @@
+package kubeapi
+
+import (
+	"context"
+
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/palestamp/ksql/pkg/cache"
+)
+
+// ListIngresses lists networking.k8s.io/v1 ingresses, clusters older than 1.19
+// are queried through extensions/v1beta1 and results are converted to v1.
+func (c *KubeConfig) ListIngresses(ctx context.Context, k8sContext, namespace string) ([]networkingv1.Ingress, error) {
+	v, err := c.cached(ctx, cache.Key{Resource: "ingresses", Context: k8sContext, Namespace: namespace}, func(ctx context.Context) (interface{}, error) {
+		cs, err := c.getClientset(k8sContext)
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			ingresses, err := cs.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return ingresses.Items, ingresses.Continue, nil
+		})
+		if err == nil {
+			return items, nil
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		v, err := c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+			legacy, err := cs.ExtensionsV1beta1().Ingresses(namespace).List(ctx, opts)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return legacy.Items, legacy.Continue, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		legacy := v.([]extensionsv1beta1.Ingress)
+
+		out := make([]networkingv1.Ingress, 0, len(legacy))
+		for _, i := range legacy {
+			out = append(out, convertIngress(i))
+		}
+
+		return out, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]networkingv1.Ingress), nil
+}
+
+func convertIngress(in extensionsv1beta1.Ingress) networkingv1.Ingress {
+	out := networkingv1.Ingress{
+		ObjectMeta: in.ObjectMeta,
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: in.Spec.IngressClassName,
+			DefaultBackend:   convertIngressBackend(in.Spec.Backend),
+		},
+	}
+
+	for _, tls := range in.Spec.TLS {
+		out.Spec.TLS = append(out.Spec.TLS, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+
+	for _, rule := range in.Spec.Rules {
+		r := networkingv1.IngressRule{Host: rule.Host}
+
+		if rule.HTTP != nil {
+			r.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, p := range rule.HTTP.Paths {
+				var pathType *networkingv1.PathType
+				if p.PathType != nil {
+					pt := networkingv1.PathType(*p.PathType)
+					pathType = &pt
+				}
+
+				var backend networkingv1.IngressBackend
+				if b := convertIngressBackend(&p.Backend); b != nil {
+					backend = *b
+				}
+
+				r.HTTP.Paths = append(r.HTTP.Paths, networkingv1.HTTPIngressPath{
+					Path:     p.Path,
+					PathType: pathType,
+					Backend:  backend,
+				})
+			}
+		}
+
+		out.Spec.Rules = append(out.Spec.Rules, r)
+	}
+
+	return out
+}
+
+func convertIngressBackend(in *extensionsv1beta1.IngressBackend) *networkingv1.IngressBackend {
+	if in == nil {
+		return nil
+	}
+
+	out := &networkingv1.IngressBackend{Resource: in.Resource}
+	if in.ServiceName != "" {
+		port := networkingv1.ServiceBackendPort{}
+		if in.ServicePort.Type == intstr.String {
+			port.Name = in.ServicePort.StrVal
+		} else {
+			port.Number = in.ServicePort.IntVal
+		}
+
+		out.Service = &networkingv1.IngressServiceBackend{
+			Name: in.ServiceName,
+			Port: port,
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,61 @@
+package kubeapi
+
+import (
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	DefaultPageSize = 500
+
+	// maxListRestarts bounds restarts caused by expired continue tokens,
+	// so list of a quickly changing collection eventually fails.
+	maxListRestarts = 3
+)
+
+// pageFunc requests single page and returns its items slice and continue token.
+type pageFunc func(opts metav1.ListOptions) (items interface{}, next string, err error)
+
+// paginate requests pages of c.pageSize items until continue token is
+// exhausted and returns concatenated items. Continue token expires when
+// requested resource version is compacted, listing is restarted from the
+// beginning then.
+func (c *KubeConfig) paginate(opts metav1.ListOptions, page pageFunc) (interface{}, error) {
+	opts.Limit = c.pageSize
+
+	var (
+		items    reflect.Value
+		restarts int
+	)
+
+	for {
+		v, next, err := page(opts)
+		if err != nil {
+			if !apierrors.IsResourceExpired(err) || opts.Continue == "" || restarts == maxListRestarts {
+				return nil, err
+			}
+
+			log.WithError(err).Debug("Continue token expired, restarting list")
+
+			restarts++
+			items = reflect.Value{}
+			opts.Continue = ""
+			continue
+		}
+
+		if items.IsValid() {
+			items = reflect.AppendSlice(items, reflect.ValueOf(v))
+		} else {
+			items = reflect.ValueOf(v)
+		}
+
+		if next == "" {
+			return items.Interface(), nil
+		}
+
+		opts.Continue = next
+	}
+}
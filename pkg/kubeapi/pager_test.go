@@ -0,0 +1,84 @@
+package kubeapi
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakePages serves items in pages of opts.Limit, continue token is an offset.
+// Tokens listed in expired are rejected once.
+func fakePages(t *testing.T, items []string, expired map[string]bool) pageFunc {
+	return func(opts metav1.ListOptions) (interface{}, string, error) {
+		if expired[opts.Continue] {
+			delete(expired, opts.Continue)
+			return nil, "", apierrors.NewResourceExpired("continue token expired")
+		}
+
+		start := 0
+		if opts.Continue != "" {
+			var err error
+			if start, err = strconv.Atoi(opts.Continue); err != nil {
+				t.Fatalf("unexpected continue token %q", opts.Continue)
+			}
+		}
+
+		end := start + int(opts.Limit)
+		if end >= len(items) {
+			return items[start:], "", nil
+		}
+
+		return items[start:end], strconv.Itoa(end), nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	c := NewKubeConfig(nil, WithPageSize(2))
+
+	v, err := c.paginate(metav1.ListOptions{}, fakePages(t, items, map[string]bool{"4": true}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := v.([]string); !reflect.DeepEqual(got, items) {
+		t.Errorf("expected=%q; got=%q", items, got)
+	}
+}
+
+func TestPaginateEmpty(t *testing.T) {
+	c := NewKubeConfig(nil)
+
+	v, err := c.paginate(metav1.ListOptions{}, fakePages(t, []string{}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := v.([]string); len(got) != 0 {
+		t.Errorf("expected no items; got=%q", got)
+	}
+}
+
+func TestPaginateExpiredRestarts(t *testing.T) {
+	c := NewKubeConfig(nil, WithPageSize(1))
+
+	var requests int
+	_, err := c.paginate(metav1.ListOptions{}, func(opts metav1.ListOptions) (interface{}, string, error) {
+		requests++
+		if opts.Continue != "" {
+			return nil, "", apierrors.NewResourceExpired("continue token expired")
+		}
+
+		return []string{"a"}, "1", nil
+	})
+	if !apierrors.IsResourceExpired(err) {
+		t.Fatalf("expected resource expired error; got=%v", err)
+	}
+
+	if expected := 2 * (maxListRestarts + 1); requests != expected {
+		t.Errorf("expected=%d requests; got=%d", expected, requests)
+	}
+}
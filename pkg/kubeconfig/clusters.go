@@ -0,0 +1,53 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Cluster describes a kubeconfig cluster entry.
+type Cluster struct {
+	Name                  string
+	Server                string
+	HasCA                 bool
+	InsecureSkipTLSVerify bool
+}
+
+// Clusters returns all clusters, or nil if "clusters" entry is missing or
+// malformed.
+func (k *Kubeconfig) Clusters() []Cluster {
+	clusters := valueOf(k.rootNode, "clusters")
+	if clusters == nil || clusters.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var out []Cluster
+	for _, c := range clusters.Content {
+		nameVal := valueOf(c, "name")
+		if nameVal == nil {
+			continue
+		}
+		cluster := valueOf(c, "cluster")
+		out = append(out, Cluster{
+			Name:   nameVal.Value,
+			Server: scalarOf(cluster, "server"),
+			HasCA: scalarOf(cluster, "certificate-authority") != "" ||
+				scalarOf(cluster, "certificate-authority-data") != "",
+			InsecureSkipTLSVerify: scalarOf(cluster, "insecure-skip-tls-verify") == "true",
+		})
+	}
+	return out
+}
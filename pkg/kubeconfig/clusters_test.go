@@ -0,0 +1,46 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKubeconfig_Clusters(t *testing.T) {
+	tl := WithMockKubeconfigLoader(`
+clusters:
+- name: a
+  cluster:
+    server: https://a.example.com
+    certificate-authority-data: abc
+- name: b
+  cluster:
+    server: https://b.example.com
+    insecure-skip-tls-verify: true`)
+	kc := New(tl)
+	if err := kc.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Cluster{
+		{Name: "a", Server: "https://a.example.com", HasCA: true},
+		{Name: "b", Server: "https://b.example.com", InsecureSkipTLSVerify: true},
+	}
+	if diff := cmp.Diff(expected, kc.Clusters()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
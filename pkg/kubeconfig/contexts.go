@@ -73,6 +73,64 @@ func (k *Kubeconfig) ContextExists(name string) bool {
 	return false
 }
 
+// Context describes a kubeconfig context entry.
+type Context struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+}
+
+// Contexts returns all contexts with their cluster, user and default
+// namespace references.
+func (k *Kubeconfig) Contexts() []Context {
+	contexts, err := k.contextsNode()
+	if err != nil {
+		return nil
+	}
+
+	var out []Context
+	for _, ctx := range contexts.Content {
+		nameVal := valueOf(ctx, "name")
+		if nameVal == nil {
+			continue
+		}
+		out = append(out, contextOf(nameVal.Value, ctx))
+	}
+	return out
+}
+
+// Context returns context with given name.
+func (k *Kubeconfig) Context(name string) (Context, error) {
+	ctx, err := k.contextNode(name)
+	if err != nil {
+		return Context{}, err
+	}
+	return contextOf(name, ctx), nil
+}
+
+func contextOf(name string, ctxNode *yaml.Node) Context {
+	ctx := valueOf(ctxNode, "context")
+	return Context{
+		Name:      name,
+		Cluster:   scalarOf(ctx, "cluster"),
+		User:      scalarOf(ctx, "user"),
+		Namespace: scalarOf(ctx, "namespace"),
+	}
+}
+
+// scalarOf returns value of scalar under key in mapNode, or "" if not found.
+func scalarOf(mapNode *yaml.Node, key string) string {
+	if mapNode == nil {
+		return ""
+	}
+	v := valueOf(mapNode, key)
+	if v == nil || v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return v.Value
+}
+
 func valueOf(mapNode *yaml.Node, key string) *yaml.Node {
 	if mapNode.Kind != yaml.MappingNode {
 		return nil
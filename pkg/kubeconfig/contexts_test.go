@@ -45,3 +45,37 @@ func TestKubeconfig_ContextNames_nonArrayContextsEntry(t *testing.T) {
 		t.Fatalf("%s", diff)
 	}
 }
+
+func TestKubeconfig_Contexts(t *testing.T) {
+	tl := WithMockKubeconfigLoader(`
+contexts:
+- name: a
+  context:
+    cluster: c1
+    user: u1
+    namespace: ns1
+- name: b
+  context:
+    cluster: c2
+    user: u2`)
+	kc := New(tl)
+	if err := kc.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Context{
+		{Name: "a", Cluster: "c1", User: "u1", Namespace: "ns1"},
+		{Name: "b", Cluster: "c2", User: "u2"},
+	}
+	if diff := cmp.Diff(expected, kc.Contexts()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+
+	ctx, err := kc.Context("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected[1], ctx); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
@@ -37,3 +37,21 @@ func (t *MockKubeconfigLoader) Output() string { return t.out.String() }
 func WithMockKubeconfigLoader(kubecfg string) *MockKubeconfigLoader {
 	return &MockKubeconfigLoader{in: strings.NewReader(kubecfg)}
 }
+
+type multiMockKubeconfigLoader []*MockKubeconfigLoader
+
+func (t multiMockKubeconfigLoader) Load() ([]ReadWriteResetCloser, error) {
+	var out []ReadWriteResetCloser
+	for _, l := range t {
+		out = append(out, ReadWriteResetCloser(l))
+	}
+	return out, nil
+}
+
+func WithMockKubeconfigLoaders(kubecfgs ...string) Loader {
+	var out multiMockKubeconfigLoader
+	for _, c := range kubecfgs {
+		out = append(out, WithMockKubeconfigLoader(c))
+	}
+	return out
+}
@@ -35,7 +35,7 @@ type Loader interface {
 type Kubeconfig struct {
 	loader Loader
 
-	f        ReadWriteResetCloser
+	files    []ReadWriteResetCloser
 	rootNode *yaml.Node
 }
 
@@ -44,33 +44,116 @@ func New(l Loader) *Kubeconfig {
 }
 
 func (k *Kubeconfig) Close() error {
-	if k.f == nil {
-		return nil
+	var firstErr error
+	for _, f := range k.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return k.f.Close()
+	return firstErr
 }
 
+// Parse reads all files returned by loader and merges them following kubectl
+// semantics: for "contexts", "clusters" and "users" the first file to define
+// an entry with a particular name wins, for any other top level key (e.g.
+// "current-context") the first file to set it wins.
 func (k *Kubeconfig) Parse() error {
 	files, err := k.loader.Load()
 	if err != nil {
 		return errors.Wrap(err, "failed to load")
 	}
+	k.files = files
 
-	// TODO since we don't support multiple kubeconfig files at the moment, there's just 1 file
-	f := files[0]
-
-	k.f = f
-	var v yaml.Node
-	if err := yaml.NewDecoder(f).Decode(&v); err != nil {
-		return errors.Wrap(err, "failed to decode")
+	var roots []*yaml.Node
+	for _, f := range files {
+		var v yaml.Node
+		if err := yaml.NewDecoder(f).Decode(&v); err != nil {
+			if err == io.EOF {
+				// empty files are ignored, same as kubectl does
+				continue
+			}
+			return errors.Wrap(err, "failed to decode")
+		}
+		if len(v.Content) == 0 {
+			continue
+		}
+		root := v.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return errors.New("kubeconfig file is not a map document")
+		}
+		roots = append(roots, root)
 	}
-	k.rootNode = v.Content[0]
-	if k.rootNode.Kind != yaml.MappingNode {
-		return errors.New("kubeconfig file is not a map document")
+	if len(roots) == 0 {
+		return errors.New("kubeconfig file is empty")
+	}
+
+	if len(roots) == 1 {
+		k.rootNode = roots[0]
+		return nil
 	}
+	k.rootNode = merge(roots)
 	return nil
 }
 
 func (k *Kubeconfig) Bytes() ([]byte, error) {
 	return yaml.Marshal(k.rootNode)
 }
+
+// namedListKeys are top level sequences of {name: ...} entries merged by name.
+var namedListKeys = map[string]bool{
+	"contexts": true,
+	"clusters": true,
+	"users":    true,
+}
+
+func merge(roots []*yaml.Node) *yaml.Node {
+	out := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+	var keys []*yaml.Node
+	values := make(map[string]*yaml.Node)
+	seen := make(map[string]map[string]bool)
+
+	for _, root := range roots {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			keyNode, valNode := root.Content[i], root.Content[i+1]
+			key := keyNode.Value
+
+			if namedListKeys[key] {
+				if valNode.Kind != yaml.SequenceNode {
+					continue
+				}
+				seq, ok := values[key]
+				if !ok {
+					seq = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+					values[key] = seq
+					seen[key] = make(map[string]bool)
+					keys = append(keys, keyNode)
+				}
+				for _, item := range valNode.Content {
+					nameNode := valueOf(item, "name")
+					if nameNode == nil || seen[key][nameNode.Value] {
+						continue
+					}
+					seen[key][nameNode.Value] = true
+					seq.Content = append(seq.Content, item)
+				}
+				continue
+			}
+
+			if _, ok := values[key]; ok {
+				continue
+			}
+			// kubectl treats empty values as unset, e.g. current-context: ""
+			if valNode.Kind == yaml.ScalarNode && valNode.Value == "" {
+				continue
+			}
+			values[key] = valNode
+			keys = append(keys, keyNode)
+		}
+	}
+
+	for _, keyNode := range keys {
+		out.Content = append(out.Content, keyNode, values[keyNode.Value])
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKubeconfig_Parse_multipleFiles(t *testing.T) {
+	tl := WithMockKubeconfigLoaders(
+		`
+current-context: ""
+contexts:
+- name: a
+  context: {cluster: a1}
+- name: b
+  context: {cluster: b1}`,
+		``,
+		`
+current-context: c
+contexts:
+- name: b
+  context: {cluster: b2}
+- name: c
+  context: {cluster: c2}`,
+	)
+	kc := New(tl)
+	if err := kc.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b", "c"}
+	if diff := cmp.Diff(expected, kc.ContextNames()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+
+	if v := kc.GetCurrentContext(); v != "c" {
+		t.Fatalf("expected=\"c\"; got=\"%s\"", v)
+	}
+
+	ctx, err := kc.contextNode("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := valueOf(valueOf(ctx, "context"), "cluster").Value; v != "b1" {
+		t.Fatalf("expected first file to win; got=\"%s\"", v)
+	}
+}
+
+func TestKubeconfig_Parse_allFilesEmpty(t *testing.T) {
+	kc := New(WithMockKubeconfigLoaders(``, ``))
+	if err := kc.Parse(); err == nil {
+		t.Fatal("expected error")
+	}
+}
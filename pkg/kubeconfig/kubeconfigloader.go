@@ -17,6 +17,7 @@ package kubeconfig
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -30,21 +31,34 @@ type StandardKubeconfigLoader struct{}
 type kubeconfigFile struct{ *os.File }
 
 func (*StandardKubeconfigLoader) Load() ([]ReadWriteResetCloser, error) {
-	cfgPath, err := kubeconfigPath()
+	paths, err := Paths()
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot determine kubeconfig path")
 	}
 
-	f, err := os.OpenFile(cfgPath, os.O_RDWR, 0)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, errors.Wrap(err, "kubeconfig file not found")
+	var files []ReadWriteResetCloser
+	for _, cfgPath := range paths {
+		f, err := os.OpenFile(cfgPath, os.O_RDWR, 0)
+		if err != nil {
+			// similar to kubectl, missing files are fine as long as
+			// at least one file from KUBECONFIG exists
+			if os.IsNotExist(err) && len(paths) > 1 {
+				continue
+			}
+			closeAll(files)
+			if os.IsNotExist(err) {
+				return nil, errors.Wrap(err, "kubeconfig file not found")
+			}
+			return nil, errors.Wrap(err, "failed to open file")
 		}
-		return nil, errors.Wrap(err, "failed to open file")
+		files = append(files, ReadWriteResetCloser(&kubeconfigFile{f}))
+	}
+
+	if len(files) == 0 {
+		return nil, errors.Errorf("none of kubeconfig files found: %s", strings.Join(paths, string(filepath.ListSeparator)))
 	}
 
-	// TODO we'll return all kubeconfig files when we start implementing multiple kubeconfig support
-	return []ReadWriteResetCloser{ReadWriteResetCloser(&kubeconfigFile{f})}, nil
+	return files, nil
 }
 
 func (kf *kubeconfigFile) Reset() error {
@@ -55,21 +69,35 @@ func (kf *kubeconfigFile) Reset() error {
 	return errors.Wrap(err, "failed to seek in file")
 }
 
-func kubeconfigPath() (string, error) {
+func closeAll(files []ReadWriteResetCloser) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// Paths returns kubeconfig files in order of precedence: files listed in
+// KUBECONFIG env var or default ~/.kube/config.
+func Paths() ([]string, error) {
 	// KUBECONFIG env var
 	if v := os.Getenv("KUBECONFIG"); v != "" {
-		list := filepath.SplitList(v)
-		if len(list) > 1 {
-			// TODO KUBECONFIG=file1:file2 currently not supported
-			return "", errors.New("multiple files in KUBECONFIG are currently not supported")
+		var paths []string
+		seen := make(map[string]bool)
+		for _, p := range filepath.SplitList(v) {
+			if p == "" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			paths = append(paths, p)
+		}
+		if len(paths) > 0 {
+			return paths, nil
 		}
-		return v, nil
 	}
 
 	// default path
 	home := HomeDir()
 	if home == "" {
-		return "", errors.New("HOME or USERPROFILE environment variable not set")
+		return nil, errors.New("HOME or USERPROFILE environment variable not set")
 	}
-	return filepath.Join(home, ".kube", "config"), nil
+	return []string{filepath.Join(home, ".kube", "config")}, nil
 }
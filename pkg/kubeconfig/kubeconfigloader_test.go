@@ -0,0 +1,70 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPaths_multipleFiles(t *testing.T) {
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+
+	sep := string(filepath.ListSeparator)
+	os.Setenv("KUBECONFIG", strings.Join([]string{"a", "", "b", "a"}, sep))
+
+	paths, err := Paths()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "b"}
+	if diff := cmp.Diff(expected, paths); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
+
+func TestStandardKubeconfigLoader_Load_skipsMissingFiles(t *testing.T) {
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	existing := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(existing, []byte(`contexts: []`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sep := string(filepath.ListSeparator)
+	os.Setenv("KUBECONFIG", strings.Join([]string{filepath.Join(dir, "missing"), existing}, sep))
+
+	files, err := new(StandardKubeconfigLoader).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeAll(files)
+
+	if len(files) != 1 {
+		t.Fatalf("expected=1; got=%d", len(files))
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Authentication methods of a kubeconfig user.
+const (
+	AuthTypeNone         = "none"
+	AuthTypeToken        = "token"
+	AuthTypeCert         = "cert"
+	AuthTypeBasic        = "basic"
+	AuthTypeExec         = "exec"
+	AuthTypeAuthProvider = "auth-provider"
+)
+
+// User describes a kubeconfig user entry.
+type User struct {
+	Name string
+	// AuthTypes lists all authentication methods configured for the user,
+	// AuthTypeNone if there are none.
+	AuthTypes []string
+	// Provider is an exec plugin command or auth-provider name.
+	Provider string
+}
+
+// Users returns all users, or nil if "users" entry is missing or malformed.
+func (k *Kubeconfig) Users() []User {
+	users := valueOf(k.rootNode, "users")
+	if users == nil || users.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var out []User
+	for _, u := range users.Content {
+		nameVal := valueOf(u, "name")
+		if nameVal == nil {
+			continue
+		}
+		out = append(out, userOf(nameVal.Value, valueOf(u, "user")))
+	}
+	return out
+}
+
+func userOf(name string, user *yaml.Node) User {
+	u := User{Name: name}
+	if user == nil || user.Kind != yaml.MappingNode {
+		u.AuthTypes = []string{AuthTypeNone}
+		return u
+	}
+
+	if exec := valueOf(user, "exec"); exec != nil {
+		u.AuthTypes = append(u.AuthTypes, AuthTypeExec)
+		u.Provider = scalarOf(exec, "command")
+	}
+	if provider := valueOf(user, "auth-provider"); provider != nil {
+		u.AuthTypes = append(u.AuthTypes, AuthTypeAuthProvider)
+		u.Provider = scalarOf(provider, "name")
+	}
+	if scalarOf(user, "token") != "" || scalarOf(user, "tokenFile") != "" {
+		u.AuthTypes = append(u.AuthTypes, AuthTypeToken)
+	}
+	if scalarOf(user, "client-certificate") != "" || scalarOf(user, "client-certificate-data") != "" {
+		u.AuthTypes = append(u.AuthTypes, AuthTypeCert)
+	}
+	if scalarOf(user, "username") != "" {
+		u.AuthTypes = append(u.AuthTypes, AuthTypeBasic)
+	}
+	if len(u.AuthTypes) == 0 {
+		u.AuthTypes = []string{AuthTypeNone}
+	}
+	return u
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeconfig
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKubeconfig_Users(t *testing.T) {
+	tl := WithMockKubeconfigLoader(`
+users:
+- name: token
+  user:
+    token: abc
+- name: cert
+  user:
+    client-certificate-data: abc
+    client-key-data: def
+- name: exec
+  user:
+    exec:
+      command: aws
+- name: gcp
+  user:
+    auth-provider:
+      name: gcp
+- name: empty
+  user: {}`)
+	kc := New(tl)
+	if err := kc.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []User{
+		{Name: "token", AuthTypes: []string{AuthTypeToken}},
+		{Name: "cert", AuthTypes: []string{AuthTypeCert}},
+		{Name: "exec", AuthTypes: []string{AuthTypeExec}, Provider: "aws"},
+		{Name: "gcp", AuthTypes: []string{AuthTypeAuthProvider}, Provider: "gcp"},
+		{Name: "empty", AuthTypes: []string{AuthTypeNone}},
+	}
+	if diff := cmp.Diff(expected, kc.Users()); diff != "" {
+		t.Fatalf("%s", diff)
+	}
+}
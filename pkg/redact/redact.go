@@ -0,0 +1,68 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const fingerprintLen = 12
+
+// Value describes possibly sensitive value.
+type Value struct {
+	// Value is either plaintext or Hash, depending on Redactor mode.
+	Value string
+	// Hash is hex encoded HMAC-SHA256 of plaintext value.
+	Hash        string
+	Length      int
+	Fingerprint string
+}
+
+// Redactor replaces sensitive values with keyed hash, so values can still be
+// compared across contexts without revealing them.
+type Redactor struct {
+	key       []byte
+	plaintext bool
+}
+
+// New creates Redactor, if key is empty random key is used, which makes hashes
+// stable only for lifetime of the process. plaintext disables redaction.
+func New(key string, plaintext bool) (*Redactor, error) {
+	k := []byte(key)
+	if len(k) == 0 {
+		k = make([]byte, sha256.Size)
+		if _, err := rand.Read(k); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Redactor{key: k, plaintext: plaintext}, nil
+}
+
+func (r *Redactor) Plaintext() bool {
+	return r.plaintext
+}
+
+func (r *Redactor) Redact(s string) Value {
+	if s == "" {
+		return Value{}
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(s))
+	hash := hex.EncodeToString(mac.Sum(nil))
+
+	v := Value{
+		Value:       hash,
+		Hash:        hash,
+		Length:      len(s),
+		Fingerprint: hash[:fingerprintLen],
+	}
+
+	if r.plaintext {
+		v.Value = s
+	}
+
+	return v
+}
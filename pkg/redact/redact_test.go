@@ -0,0 +1,46 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r, err := New("key", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := r.Redact("secret"), r.Redact("secret")
+	if a != b {
+		t.Fatalf("expected stable hash; got=%v, %v", a, b)
+	}
+
+	if a.Value == "secret" || a.Value != a.Hash {
+		t.Fatalf("expected value to be replaced with hash; got=%v", a)
+	}
+
+	if a.Length != 6 || a.Fingerprint != a.Hash[:fingerprintLen] {
+		t.Fatalf("unexpected length or fingerprint; got=%v", a)
+	}
+
+	other, err := New("other-key", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other.Redact("secret").Hash == a.Hash {
+		t.Fatal("expected hash to depend on key")
+	}
+}
+
+func TestRedactor_Redact_plaintext(t *testing.T) {
+	r, err := New("key", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := r.Redact("secret")
+	if v.Value != "secret" || v.Hash == "" {
+		t.Fatalf("expected plaintext value with hash; got=%v", v)
+	}
+}
@@ -0,0 +1,112 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/palestamp/ksql/pkg/cache"
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+var cacheColumns = []table.ColumnDefinition{
+	table.TextColumn("resource"),
+	table.TextColumn("context"),
+	table.TextColumn("namespace"),
+	table.TextColumn("selector"),
+	table.IntegerColumn("items"),
+	table.BigIntColumn("created_at"),
+	table.BigIntColumn("expires_at"),
+}
+
+// Cache lists cached API responses.
+type Cache struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewCache(kc *kubeapi.KubeConfig) *Cache {
+	return &Cache{kc: kc}
+}
+
+func (d *Cache) Columns() []table.ColumnDefinition {
+	return cacheColumns
+}
+
+func (d *Cache) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "cache")
+	logQueryContext(logger, queryContext)
+
+	var rows []map[string]string
+	for _, e := range d.kc.CacheEntries() {
+		if !matchCacheKey(e.Key, queryContext) {
+			continue
+		}
+
+		rows = append(rows, cacheEntryRow(e))
+	}
+
+	return rows, nil
+}
+
+// CacheFlush drops cached API responses matched by query constraints,
+// e.g. "select * from k8s_cache_flush where context = 'prod'".
+// Flushed entries are returned as rows.
+type CacheFlush struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewCacheFlush(kc *kubeapi.KubeConfig) *CacheFlush {
+	return &CacheFlush{kc: kc}
+}
+
+func (d *CacheFlush) Columns() []table.ColumnDefinition {
+	return cacheColumns
+}
+
+func (d *CacheFlush) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "cache-flush")
+	logQueryContext(logger, queryContext)
+
+	flushed := d.kc.FlushCache(func(k cache.Key) bool {
+		return matchCacheKey(k, queryContext)
+	})
+
+	logger.Infof("flushed %d entries", len(flushed))
+
+	var rows []map[string]string
+	for _, e := range flushed {
+		rows = append(rows, cacheEntryRow(e))
+	}
+
+	return rows, nil
+}
+
+func matchCacheKey(k cache.Key, qc table.QueryContext) bool {
+	return len(filterConstraint([]string{k.Resource}, qc.Constraints["resource"])) != 0 &&
+		len(filterConstraint([]string{k.Context}, qc.Constraints["context"])) != 0 &&
+		len(filterConstraint([]string{k.Namespace}, qc.Constraints["namespace"])) != 0 &&
+		len(filterConstraint([]string{k.Selector}, qc.Constraints["selector"])) != 0
+}
+
+func cacheEntryRow(e cache.Entry) map[string]string {
+	return map[string]string{
+		"resource":   e.Key.Resource,
+		"context":    e.Key.Context,
+		"namespace":  e.Key.Namespace,
+		"selector":   e.Key.Selector,
+		"items":      strconv.Itoa(e.Items),
+		"created_at": formatTime(e.CreatedAt),
+		"expires_at": formatTime(e.ExpiresAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return strconv.FormatInt(t.Unix(), 10)
+}
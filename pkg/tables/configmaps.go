@@ -0,0 +1,105 @@
+package tables
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type ConfigMaps struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewConfigMaps(kc *kubeapi.KubeConfig) *ConfigMaps {
+	return &ConfigMaps{kc: kc}
+}
+
+func (d *ConfigMaps) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("name"),
+		table.TextColumn("key"),
+		table.TextColumn("value"),
+		table.TextColumn("is_binary"),
+		table.IntegerColumn("size"),
+	}
+}
+
+func (d *ConfigMaps) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "configmaps")
+	logQueryContext(logger, queryContext)
+
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]map[string]string, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		c := namespaces[i]
+
+		configMaps, err := d.kc.ListConfigMaps(ctx, c.Context, c.Namespace)
+		if err != nil {
+			return skipFailed(ctx, d.kc, err)
+		}
+
+		for _, cm := range configMaps {
+			if len(filterConstraint([]string{cm.Name}, queryContext.Constraints["name"])) == 0 {
+				continue
+			}
+
+			keys := make([]string, 0, len(cm.Data))
+			for k := range cm.Data {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				v := cm.Data[k]
+				results[i] = append(results[i], map[string]string{
+					"context":   c.Context,
+					"namespace": c.Namespace,
+					"name":      cm.Name,
+					"key":       k,
+					"value":     v,
+					"is_binary": fmt.Sprintf("%t", false),
+					"size":      strconv.Itoa(len(v)),
+				})
+			}
+
+			// binary values are base64 encoded, size is reported for decoded value
+			for _, k := range sortedKeys(cm.BinaryData) {
+				v := cm.BinaryData[k]
+				results[i] = append(results[i], map[string]string{
+					"context":   c.Context,
+					"namespace": c.Namespace,
+					"name":      cm.Name,
+					"key":       k,
+					"value":     base64.StdEncoding.EncodeToString(v),
+					"is_binary": fmt.Sprintf("%t", true),
+					"size":      strconv.Itoa(len(v)),
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
+}
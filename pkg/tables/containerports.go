@@ -0,0 +1,73 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type ContainerPorts struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewContainerPorts(kc *kubeapi.KubeConfig) *ContainerPorts {
+	return &ContainerPorts{kc: kc}
+}
+
+func (d *ContainerPorts) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
+		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
+		table.TextColumn("name"),
+		table.IntegerColumn("container_port"),
+		table.TextColumn("protocol"),
+		table.IntegerColumn("host_port"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *ContainerPorts) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "container-ports")
+	logQueryContext(logger, queryContext)
+
+	selector := labelSelector(queryContext)
+
+	containers, err := listContainers(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, c := range containers {
+		for _, p := range c.Container.Ports {
+			var hostPort string
+			if p.HostPort != 0 {
+				hostPort = strconv.Itoa(int(p.HostPort))
+			}
+
+			rows = append(rows, map[string]string{
+				"context":        c.Context,
+				"namespace":      c.Namespace,
+				"workload_kind":  c.WorkloadKind,
+				"deployment":     c.Workload,
+				"container":      c.Container.Name,
+				"container_type": c.ContainerType,
+				"name":           p.Name,
+				"container_port": strconv.Itoa(int(p.ContainerPort)),
+				"protocol":       string(p.Protocol),
+				"host_port":      hostPort,
+				"label_selector": selector,
+			})
+		}
+	}
+
+	return rows, nil
+}
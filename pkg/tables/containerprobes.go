@@ -0,0 +1,127 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type ContainerProbes struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewContainerProbes(kc *kubeapi.KubeConfig) *ContainerProbes {
+	return &ContainerProbes{kc: kc}
+}
+
+func (d *ContainerProbes) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
+		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
+		table.TextColumn("probe"),
+		table.TextColumn("handler"),
+		table.TextColumn("scheme"),
+		table.TextColumn("host"),
+		table.TextColumn("path"),
+		table.TextColumn("port"),
+		table.TextColumn("command"),
+		table.IntegerColumn("initial_delay_seconds"),
+		table.IntegerColumn("period_seconds"),
+		table.IntegerColumn("timeout_seconds"),
+		table.IntegerColumn("success_threshold"),
+		table.IntegerColumn("failure_threshold"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *ContainerProbes) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "container-probes")
+	logQueryContext(logger, queryContext)
+
+	selector := labelSelector(queryContext)
+
+	containers, err := listContainers(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, c := range containers {
+		probes := []struct {
+			name  string
+			probe *corev1.Probe
+		}{
+			{"liveness", c.Container.LivenessProbe},
+			{"readiness", c.Container.ReadinessProbe},
+			{"startup", c.Container.StartupProbe},
+		}
+
+		for _, p := range probes {
+			if p.probe == nil {
+				continue
+			}
+
+			row := probeRow(p.probe)
+			row["context"] = c.Context
+			row["namespace"] = c.Namespace
+			row["workload_kind"] = c.WorkloadKind
+			row["deployment"] = c.Workload
+			row["container"] = c.Container.Name
+			row["container_type"] = c.ContainerType
+			row["probe"] = p.name
+			row["label_selector"] = selector
+
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// probeRow reports probe timings with API server defaults applied, so probes
+// relying on defaults compare equal to explicitly configured ones.
+func probeRow(p *corev1.Probe) map[string]string {
+	row := map[string]string{
+		"initial_delay_seconds": strconv.Itoa(int(p.InitialDelaySeconds)),
+		"period_seconds":        strconv.Itoa(int(orDefault(p.PeriodSeconds, 10))),
+		"timeout_seconds":       strconv.Itoa(int(orDefault(p.TimeoutSeconds, 1))),
+		"success_threshold":     strconv.Itoa(int(orDefault(p.SuccessThreshold, 1))),
+		"failure_threshold":     strconv.Itoa(int(orDefault(p.FailureThreshold, 3))),
+	}
+
+	switch {
+	case p.HTTPGet != nil:
+		row["handler"] = "http"
+		row["scheme"] = string(p.HTTPGet.Scheme)
+		row["host"] = p.HTTPGet.Host
+		row["path"] = p.HTTPGet.Path
+		row["port"] = p.HTTPGet.Port.String()
+	case p.TCPSocket != nil:
+		row["handler"] = "tcp"
+		row["host"] = p.TCPSocket.Host
+		row["port"] = p.TCPSocket.Port.String()
+	case p.Exec != nil:
+		row["handler"] = "exec"
+		row["command"] = strings.Join(p.Exec.Command, " ")
+	}
+
+	return row
+}
+
+func orDefault(v, def int32) int32 {
+	if v == 0 {
+		return def
+	}
+
+	return v
+}
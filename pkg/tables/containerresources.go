@@ -0,0 +1,110 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type ContainerResources struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewContainerResources(kc *kubeapi.KubeConfig) *ContainerResources {
+	return &ContainerResources{kc: kc}
+}
+
+func (d *ContainerResources) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
+		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
+		table.TextColumn("image"),
+		table.BigIntColumn("cpu_request"),
+		table.BigIntColumn("cpu_limit"),
+		table.BigIntColumn("memory_request"),
+		table.BigIntColumn("memory_limit"),
+		table.BigIntColumn("ephemeral_storage_request"),
+		table.BigIntColumn("ephemeral_storage_limit"),
+		table.TextColumn("extended_requests"),
+		table.TextColumn("extended_limits"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+// Generate reports cpu in millicores, memory and ephemeral storage in bytes,
+// missing requests and limits are reported as NULL. Extended resources (e.g.
+// nvidia.com/gpu) are formatted as "name=value" list.
+func (d *ContainerResources) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "container-resources")
+	logQueryContext(logger, queryContext)
+
+	selector := labelSelector(queryContext)
+
+	containers, err := listContainers(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, c := range containers {
+		requests, limits := c.Container.Resources.Requests, c.Container.Resources.Limits
+
+		rows = append(rows, map[string]string{
+			"context":                   c.Context,
+			"namespace":                 c.Namespace,
+			"workload_kind":             c.WorkloadKind,
+			"deployment":                c.Workload,
+			"container":                 c.Container.Name,
+			"container_type":            c.ContainerType,
+			"image":                     c.Container.Image,
+			"cpu_request":               milliValue(requests, corev1.ResourceCPU),
+			"cpu_limit":                 milliValue(limits, corev1.ResourceCPU),
+			"memory_request":            value(requests, corev1.ResourceMemory),
+			"memory_limit":              value(limits, corev1.ResourceMemory),
+			"ephemeral_storage_request": value(requests, corev1.ResourceEphemeralStorage),
+			"ephemeral_storage_limit":   value(limits, corev1.ResourceEphemeralStorage),
+			"extended_requests":         formatLabels(extendedResources(requests)),
+			"extended_limits":           formatLabels(extendedResources(limits)),
+			"label_selector":            selector,
+		})
+	}
+
+	return rows, nil
+}
+
+var standardResources = map[corev1.ResourceName]bool{
+	corev1.ResourceCPU:              true,
+	corev1.ResourceMemory:           true,
+	corev1.ResourceEphemeralStorage: true,
+}
+
+func extendedResources(list corev1.ResourceList) map[string]string {
+	out := make(map[string]string)
+	for name, q := range list {
+		if standardResources[name] {
+			continue
+		}
+
+		out[string(name)] = quantityString(q)
+	}
+
+	return out
+}
+
+func quantityString(q resource.Quantity) string {
+	if v, ok := q.AsInt64(); ok {
+		return strconv.FormatInt(v, 10)
+	}
+
+	return q.String()
+}
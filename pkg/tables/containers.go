@@ -21,9 +21,13 @@ func (d *Containers) Columns() []table.ColumnDefinition {
 	return []table.ColumnDefinition{
 		table.TextColumn("context"),
 		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
 		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
 		table.TextColumn("image"),
 		table.TextColumn("tag"),
+		table.TextColumn("label_selector"),
 	}
 }
 
@@ -31,7 +35,9 @@ func (d *Containers) Generate(ctx context.Context, queryContext table.QueryConte
 	logger := log.WithField("generate", "containers")
 	logQueryContext(logger, queryContext)
 
-	cs, err := listContainers(d.kc, queryContext)
+	selector := labelSelector(queryContext)
+
+	cs, err := listContainers(ctx, d.kc, queryContext)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +47,15 @@ func (d *Containers) Generate(ctx context.Context, queryContext table.QueryConte
 		image, tag := splitTag(c.Container.Image)
 
 		rows = append(rows, map[string]string{
-			"context":    c.Context,
-			"namespace":  c.Namespace,
-			"deployment": c.Deployment,
-			"image":      image,
-			"tag":        tag,
+			"context":        c.Context,
+			"namespace":      c.Namespace,
+			"workload_kind":  c.WorkloadKind,
+			"deployment":     c.Workload,
+			"container":      c.Container.Name,
+			"container_type": c.ContainerType,
+			"image":          image,
+			"tag":            tag,
+			"label_selector": selector,
 		})
 	}
 
@@ -0,0 +1,147 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type ContainerStatuses struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewContainerStatuses(kc *kubeapi.KubeConfig) *ContainerStatuses {
+	return &ContainerStatuses{kc: kc}
+}
+
+func (d *ContainerStatuses) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("pod"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
+		table.TextColumn("ready"),
+		table.IntegerColumn("restart_count"),
+		table.TextColumn("state"),
+		table.TextColumn("state_reason"),
+		table.TextColumn("last_termination_reason"),
+		table.TextColumn("last_exit_code"),
+		table.TextColumn("image"),
+		table.TextColumn("image_id"),
+		table.TextColumn("digest"),
+	}
+}
+
+func (d *ContainerStatuses) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "container-statuses")
+	logQueryContext(logger, queryContext)
+
+	// pod name constraint is applied by listPods through "name" column,
+	// this table exposes it as "pod".
+	queryContext.Constraints = renameConstraint(queryContext.Constraints, "pod", "name")
+
+	pods, err := listPods(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, p := range pods {
+		statuses := []struct {
+			containerType string
+			statuses      []corev1.ContainerStatus
+		}{
+			{containerTypeInit, p.Pod.Status.InitContainerStatuses},
+			{containerTypeContainer, p.Pod.Status.ContainerStatuses},
+			{containerTypeEphemeral, p.Pod.Status.EphemeralContainerStatuses},
+		}
+
+		for _, st := range statuses {
+			if len(filterConstraint([]string{st.containerType}, queryContext.Constraints["container_type"])) == 0 {
+				continue
+			}
+
+			for _, s := range st.statuses {
+				if len(filterConstraint([]string{s.Name}, queryContext.Constraints["container"])) == 0 {
+					continue
+				}
+
+				state, reason := containerState(s.State)
+
+				var lastReason, lastExitCode string
+				if t := s.LastTerminationState.Terminated; t != nil {
+					lastReason = t.Reason
+					lastExitCode = strconv.Itoa(int(t.ExitCode))
+				}
+
+				rows = append(rows, map[string]string{
+					"context":                 p.Context,
+					"namespace":               p.Namespace,
+					"pod":                     p.Pod.Name,
+					"container":               s.Name,
+					"container_type":          st.containerType,
+					"ready":                   fmt.Sprintf("%t", s.Ready),
+					"restart_count":           strconv.Itoa(int(s.RestartCount)),
+					"state":                   state,
+					"state_reason":            reason,
+					"last_termination_reason": lastReason,
+					"last_exit_code":          lastExitCode,
+					"image":                   s.Image,
+					"image_id":                s.ImageID,
+					"digest":                  splitDigest(s.ImageID),
+				})
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func containerState(s corev1.ContainerState) (string, string) {
+	switch {
+	case s.Running != nil:
+		return "running", ""
+	case s.Waiting != nil:
+		return "waiting", s.Waiting.Reason
+	case s.Terminated != nil:
+		return "terminated", s.Terminated.Reason
+	}
+
+	return "unknown", ""
+}
+
+// splitDigest extracts "sha256:..." part from image id reported by
+// container runtime, e.g. "docker-pullable://repo/image@sha256:...".
+func splitDigest(imageID string) string {
+	i := strings.LastIndex(imageID, "@")
+	if i == -1 {
+		return ""
+	}
+
+	return imageID[i+1:]
+}
+
+func renameConstraint(cs map[string]table.ConstraintList, from, to string) map[string]table.ConstraintList {
+	out := make(map[string]table.ConstraintList, len(cs))
+	for k, v := range cs {
+		if k == to {
+			continue
+		}
+
+		if k == from {
+			k = to
+		}
+
+		out[k] = v
+	}
+
+	return out
+}
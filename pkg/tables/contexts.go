@@ -20,6 +20,9 @@ type Contexts struct {
 func (d *Contexts) Columns() []table.ColumnDefinition {
 	return []table.ColumnDefinition{
 		table.TextColumn("name"),
+		table.TextColumn("cluster"),
+		table.TextColumn("user"),
+		table.TextColumn("namespace"),
 	}
 }
 
@@ -27,15 +30,22 @@ func (d *Contexts) Generate(ctx context.Context, queryContext table.QueryContext
 	logger := log.WithField("generate", "contexts")
 	logQueryContext(logger, queryContext)
 
-	contexts, err := d.kc.ListContexts()
+	contexts, err := d.kc.ListContextDetails()
 	if err != nil {
 		return nil, err
 	}
 
 	var rows []map[string]string
-	for _, c := range filterConstraint(contexts, queryContext.Constraints["name"]) {
+	for _, c := range contexts {
+		if len(filterConstraint([]string{c.Name}, queryContext.Constraints["name"])) == 0 {
+			continue
+		}
+
 		rows = append(rows, map[string]string{
-			"name": c,
+			"name":      c.Name,
+			"cluster":   c.Cluster,
+			"user":      c.User,
+			"namespace": c.Namespace,
 		})
 	}
 
@@ -0,0 +1,373 @@
+package tables
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+const (
+	envSourceValue            = "value"
+	envSourceSecretRef        = "secret_ref"
+	envSourceConfigMapRef     = "configmap_ref"
+	envSourceFieldRef         = "field_ref"
+	envSourceResourceFieldRef = "resource_field_ref"
+	envSourceEnvFromSecret    = "env_from_secret"
+	envSourceEnvFromConfigMap = "env_from_configmap"
+)
+
+// envSources lists objects referenced by env vars, it is implemented by
+// kubeapi.KubeConfig.
+type envSources interface {
+	requestPolicy
+	ListSecrets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]corev1.Secret, error)
+	ListConfigMaps(ctx context.Context, k8sContext, namespace string) ([]corev1.ConfigMap, error)
+}
+
+var _ envSources = (*kubeapi.KubeConfig)(nil)
+
+// resolveEnv returns environment of container the same way kubelet builds it:
+// envFrom sources first, then env entries, later definitions override
+// earlier ones. Values referencing Secrets and ConfigMaps are looked up
+// through src. Values which cannot be resolved from pod template (e.g.
+// status.podIP) are left empty.
+func resolveEnv(ctx context.Context, src envSources, c ContainerWrap) ([]EnvVar, error) {
+	r := &envResolver{src: src, c: c}
+
+	var out []EnvVar
+	index := make(map[string]int)
+	set := func(e EnvVar) {
+		if i, ok := index[e.Name]; ok {
+			out[i] = e
+			return
+		}
+
+		index[e.Name] = len(out)
+		out = append(out, e)
+	}
+
+	for _, from := range c.Container.EnvFrom {
+		envs, err := r.envFrom(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range envs {
+			set(e)
+		}
+	}
+
+	lookup := func(name string) (string, bool) {
+		i, ok := index[name]
+		if !ok {
+			return "", false
+		}
+
+		return out[i].Value, true
+	}
+
+	for _, env := range c.Container.Env {
+		e, err := r.env(ctx, env, lookup)
+		if err != nil {
+			return nil, err
+		}
+
+		set(e)
+	}
+
+	return out, nil
+}
+
+type envResolver struct {
+	src envSources
+	c   ContainerWrap
+}
+
+func (r *envResolver) env(ctx context.Context, env corev1.EnvVar, lookup func(string) (string, bool)) (EnvVar, error) {
+	e := EnvVar{Name: env.Name}
+
+	if env.ValueFrom == nil {
+		e.Source = envSourceValue
+		e.Value = expandEnv(env.Value, lookup)
+		return e, nil
+	}
+
+	from := env.ValueFrom
+	switch {
+	case from.SecretKeyRef != nil:
+		e.IsSecret = true
+		e.SecretName = from.SecretKeyRef.Name
+		e.SecretKey = from.SecretKeyRef.Key
+		e.Source = envSourceSecretRef
+		e.SourceName = from.SecretKeyRef.Name
+		e.SourceKey = from.SecretKeyRef.Key
+
+		s, err := r.secret(ctx, from.SecretKeyRef.Name)
+		if err != nil {
+			return e, err
+		}
+
+		if s != nil {
+			e.Value = string(s.Data[from.SecretKeyRef.Key])
+		}
+
+	case from.ConfigMapKeyRef != nil:
+		e.Source = envSourceConfigMapRef
+		e.SourceName = from.ConfigMapKeyRef.Name
+		e.SourceKey = from.ConfigMapKeyRef.Key
+
+		cm, err := r.configMap(ctx, from.ConfigMapKeyRef.Name)
+		if err != nil {
+			return e, err
+		}
+
+		if cm != nil {
+			e.Value = cm.Data[from.ConfigMapKeyRef.Key]
+		}
+
+	case from.FieldRef != nil:
+		e.Source = envSourceFieldRef
+		e.SourceName = from.FieldRef.FieldPath
+		e.Value = r.field(from.FieldRef.FieldPath)
+
+	case from.ResourceFieldRef != nil:
+		e.Source = envSourceResourceFieldRef
+		e.SourceName = from.ResourceFieldRef.Resource
+
+		if c, ok := r.container(from.ResourceFieldRef.ContainerName); ok {
+			e.Value = resourceField(c, from.ResourceFieldRef)
+		}
+	}
+
+	return e, nil
+}
+
+func (r *envResolver) envFrom(ctx context.Context, from corev1.EnvFromSource) ([]EnvVar, error) {
+	var out []EnvVar
+
+	switch {
+	case from.SecretRef != nil:
+		s, err := r.secret(ctx, from.SecretRef.Name)
+		if err != nil || s == nil {
+			return nil, err
+		}
+
+		for _, k := range sortedKeys(s.Data) {
+			out = append(out, EnvVar{
+				Name:       from.Prefix + k,
+				Value:      string(s.Data[k]),
+				IsSecret:   true,
+				SecretName: s.Name,
+				SecretKey:  k,
+				Source:     envSourceEnvFromSecret,
+				SourceName: s.Name,
+				SourceKey:  k,
+			})
+		}
+
+	case from.ConfigMapRef != nil:
+		cm, err := r.configMap(ctx, from.ConfigMapRef.Name)
+		if err != nil || cm == nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(cm.Data))
+		for k := range cm.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			out = append(out, EnvVar{
+				Name:       from.Prefix + k,
+				Value:      cm.Data[k],
+				Source:     envSourceEnvFromConfigMap,
+				SourceName: cm.Name,
+				SourceKey:  k,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// secret returns nil if secret does not exist or cannot be fetched.
+func (r *envResolver) secret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secrets, err := r.src.ListSecrets(ctx, r.c.Context, r.c.Namespace, "")
+	if err != nil {
+		return nil, skipFailed(ctx, r.src, err)
+	}
+
+	for i := range secrets {
+		if secrets[i].Name == name {
+			return &secrets[i], nil
+		}
+	}
+
+	log.
+		WithField("context", r.c.Context).
+		WithField("namespace", r.c.Namespace).
+		Debugf("secret %q referenced by %q not found", name, r.c.Workload)
+
+	return nil, nil
+}
+
+// configMap returns nil if config map does not exist or cannot be fetched.
+func (r *envResolver) configMap(ctx context.Context, name string) (*corev1.ConfigMap, error) {
+	configMaps, err := r.src.ListConfigMaps(ctx, r.c.Context, r.c.Namespace)
+	if err != nil {
+		return nil, skipFailed(ctx, r.src, err)
+	}
+
+	for i := range configMaps {
+		if configMaps[i].Name == name {
+			return &configMaps[i], nil
+		}
+	}
+
+	log.
+		WithField("context", r.c.Context).
+		WithField("namespace", r.c.Namespace).
+		Debugf("config map %q referenced by %q not found", name, r.c.Workload)
+
+	return nil, nil
+}
+
+// container returns container referenced by resourceFieldRef, empty name
+// refers to the resolved container itself.
+func (r *envResolver) container(name string) (corev1.Container, bool) {
+	if name == "" || name == r.c.Container.Name {
+		return r.c.Container, true
+	}
+
+	if r.c.Template != nil {
+		spec := r.c.Template.Spec
+		for _, cs := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+			for _, c := range cs {
+				if c.Name == name {
+					return c, true
+				}
+			}
+		}
+
+		for _, ec := range spec.EphemeralContainers {
+			if ec.Name == name {
+				return corev1.Container(ec.EphemeralContainerCommon), true
+			}
+		}
+	}
+
+	log.
+		WithField("context", r.c.Context).
+		WithField("namespace", r.c.Namespace).
+		Debugf("container %q referenced by %q not found", name, r.c.Workload)
+
+	return corev1.Container{}, false
+}
+
+var metadataMapField = regexp.MustCompile(`^metadata\.(labels|annotations)\['(.+)'\]$`)
+
+// field resolves fields available in pod template, runtime fields like
+// metadata.name of a pod or status.podIP are unknown.
+func (r *envResolver) field(path string) string {
+	if path == "metadata.namespace" {
+		return r.c.Namespace
+	}
+
+	if r.c.Template == nil {
+		return ""
+	}
+
+	m := metadataMapField.FindStringSubmatch(path)
+	if m == nil {
+		return ""
+	}
+
+	if m[1] == "labels" {
+		return r.c.Template.Labels[m[2]]
+	}
+
+	return r.c.Template.Annotations[m[2]]
+}
+
+// resourceField converts container resource to string the same way
+// downward API does, value is rounded up to divisor.
+func resourceField(c corev1.Container, ref *corev1.ResourceFieldSelector) string {
+	parts := strings.SplitN(ref.Resource, ".", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	var list corev1.ResourceList
+	switch parts[0] {
+	case "limits":
+		list = c.Resources.Limits
+	case "requests":
+		list = c.Resources.Requests
+	default:
+		return ""
+	}
+
+	q, ok := list[corev1.ResourceName(parts[1])]
+	if !ok {
+		return ""
+	}
+
+	divisor := resource.MustParse("1")
+	if !ref.Divisor.IsZero() {
+		divisor = ref.Divisor
+	}
+
+	if parts[1] == string(corev1.ResourceCPU) {
+		return strconv.FormatInt(int64(math.Ceil(float64(q.MilliValue())/float64(divisor.MilliValue()))), 10)
+	}
+
+	return strconv.FormatInt(int64(math.Ceil(float64(q.Value())/float64(divisor.Value()))), 10)
+}
+
+// expandEnv replaces $(VAR) references with values of previously defined
+// variables, "$$" escapes "$", unknown references are left as is.
+func expandEnv(s string, lookup func(string) (string, bool)) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			buf.WriteByte('$')
+			i++
+			continue
+		case '(':
+			if j := strings.IndexByte(s[i+2:], ')'); j != -1 {
+				ref := s[i : i+3+j]
+				if v, ok := lookup(s[i+2 : i+2+j]); ok {
+					buf.WriteString(v)
+				} else {
+					buf.WriteString(ref)
+				}
+				i += 2 + j
+				continue
+			}
+		}
+
+		buf.WriteByte(s[i])
+	}
+
+	return buf.String()
+}
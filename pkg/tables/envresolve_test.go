@@ -0,0 +1,266 @@
+package tables
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExpandEnv(t *testing.T) {
+	vars := map[string]string{"HOST": "db", "PORT": "5432"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	cases := map[string]string{
+		"plain":                    "plain",
+		"$(HOST):$(PORT)":          "db:5432",
+		"$(UNKNOWN)":               "$(UNKNOWN)",
+		"$$(HOST)":                 "$(HOST)",
+		"price: 5$":                "price: 5$",
+		"$(HOST":                   "$(HOST",
+		"postgres://$(HOST)/app$$": "postgres://db/app$",
+	}
+
+	for in, expected := range cases {
+		if got := expandEnv(in, lookup); got != expected {
+			t.Errorf("expandEnv(%q): expected=%q; got=%q", in, expected, got)
+		}
+	}
+}
+
+func TestResourceField(t *testing.T) {
+	c := corev1.Container{
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1500m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		},
+	}
+
+	cases := []struct {
+		ref      corev1.ResourceFieldSelector
+		expected string
+	}{
+		{corev1.ResourceFieldSelector{Resource: "limits.cpu"}, "2"},
+		{corev1.ResourceFieldSelector{Resource: "limits.cpu", Divisor: resource.MustParse("1m")}, "1500"},
+		{corev1.ResourceFieldSelector{Resource: "limits.memory", Divisor: resource.MustParse("1Mi")}, "128"},
+		{corev1.ResourceFieldSelector{Resource: "requests.memory"}, ""},
+	}
+
+	for _, tc := range cases {
+		if got := resourceField(c, &tc.ref); got != tc.expected {
+			t.Errorf("resourceField(%s): expected=%q; got=%q", tc.ref.Resource, tc.expected, got)
+		}
+	}
+}
+
+func TestResolveEnvResourceFieldContainerName(t *testing.T) {
+	limits := func(memory string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		}
+	}
+
+	ref := func(name, containerName string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{
+				ContainerName: containerName,
+				Resource:      "limits.memory",
+				Divisor:       resource.MustParse("1Mi"),
+			}},
+		}
+	}
+
+	app := corev1.Container{
+		Name:      "app",
+		Resources: limits("128Mi"),
+		Env: []corev1.EnvVar{
+			ref("OWN", ""),
+			ref("SIDECAR", "sidecar"),
+			ref("INIT", "init"),
+			ref("MISSING", "missing"),
+		},
+	}
+
+	c := ContainerWrap{
+		Container: app,
+		Template: &corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init", Resources: limits("32Mi")}},
+			Containers:     []corev1.Container{app, {Name: "sidecar", Resources: limits("64Mi")}},
+		}},
+	}
+
+	envs, err := resolveEnv(context.Background(), &fakeEnvSources{}, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"OWN": "128", "SIDECAR": "64", "INIT": "32", "MISSING": ""}
+	for _, e := range envs {
+		if e.Value != expected[e.Name] {
+			t.Errorf("%s: expected=%q; got=%q", e.Name, expected[e.Name], e.Value)
+		}
+	}
+}
+
+type fakeEnvSources struct {
+	secrets    []corev1.Secret
+	configMaps []corev1.ConfigMap
+	err        error
+	failFast   bool
+}
+
+func (f *fakeEnvSources) FailFast() bool {
+	return f.failFast
+}
+
+func (f *fakeEnvSources) ListSecrets(ctx context.Context, k8sContext, namespace, labelSelector string) ([]corev1.Secret, error) {
+	return f.secrets, f.err
+}
+
+func (f *fakeEnvSources) ListConfigMaps(ctx context.Context, k8sContext, namespace string) ([]corev1.ConfigMap, error) {
+	return f.configMaps, f.err
+}
+
+func TestResolveEnv(t *testing.T) {
+	src := &fakeEnvSources{
+		secrets: []corev1.Secret{{
+			ObjectMeta: metav1.ObjectMeta{Name: "db"},
+			Data:       map[string][]byte{"password": []byte("s3cret"), "user": []byte("admin")},
+		}},
+		configMaps: []corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Data:       map[string]string{"HOST": "db.local", "PORT": "5432"},
+		}},
+	}
+
+	secretKeyRef := func(name, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}}
+	}
+
+	configMapKeyRef := func(name, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}}
+	}
+
+	cases := []struct {
+		name      string
+		container corev1.Container
+		expected  []EnvVar
+	}{
+		{
+			name: "env overrides envFrom",
+			container: corev1.Container{
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app"}}},
+					{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "PORT", Value: "6432"},
+				},
+			},
+			expected: []EnvVar{
+				{Name: "HOST", Value: "db.local", Source: envSourceEnvFromConfigMap, SourceName: "app", SourceKey: "HOST"},
+				{Name: "PORT", Value: "6432", Source: envSourceValue},
+				{Name: "DB_password", Value: "s3cret", IsSecret: true, SecretName: "db", SecretKey: "password", Source: envSourceEnvFromSecret, SourceName: "db", SourceKey: "password"},
+				{Name: "DB_user", Value: "admin", IsSecret: true, SecretName: "db", SecretKey: "user", Source: envSourceEnvFromSecret, SourceName: "db", SourceKey: "user"},
+			},
+		},
+		{
+			name: "expansion uses earlier vars only",
+			container: corev1.Container{
+				Env: []corev1.EnvVar{
+					{Name: "HOST", ValueFrom: configMapKeyRef("app", "HOST")},
+					{Name: "URL", Value: "postgres://$(HOST):$(PORT)"},
+					{Name: "PORT", Value: "5432"},
+				},
+			},
+			expected: []EnvVar{
+				{Name: "HOST", Value: "db.local", Source: envSourceConfigMapRef, SourceName: "app", SourceKey: "HOST"},
+				{Name: "URL", Value: "postgres://db.local:$(PORT)", Source: envSourceValue},
+				{Name: "PORT", Value: "5432", Source: envSourceValue},
+			},
+		},
+		{
+			name: "key references",
+			container: corev1.Container{
+				Env: []corev1.EnvVar{
+					{Name: "PASSWORD", ValueFrom: secretKeyRef("db", "password")},
+					{Name: "PORT", ValueFrom: configMapKeyRef("app", "PORT")},
+					{Name: "MISSING_KEY", ValueFrom: configMapKeyRef("app", "missing")},
+				},
+			},
+			expected: []EnvVar{
+				{Name: "PASSWORD", Value: "s3cret", IsSecret: true, SecretName: "db", SecretKey: "password", Source: envSourceSecretRef, SourceName: "db", SourceKey: "password"},
+				{Name: "PORT", Value: "5432", Source: envSourceConfigMapRef, SourceName: "app", SourceKey: "PORT"},
+				{Name: "MISSING_KEY", Source: envSourceConfigMapRef, SourceName: "app", SourceKey: "missing"},
+			},
+		},
+		{
+			name: "missing secret",
+			container: corev1.Container{
+				EnvFrom: []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "PASSWORD", ValueFrom: secretKeyRef("missing", "password")},
+				},
+			},
+			expected: []EnvVar{
+				{Name: "PASSWORD", IsSecret: true, SecretName: "missing", SecretKey: "password", Source: envSourceSecretRef, SourceName: "missing", SourceKey: "password"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := resolveEnv(context.Background(), src, ContainerWrap{Container: tc.container})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s:\nexpected=%+v\ngot=%+v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestResolveEnvFailedLookup(t *testing.T) {
+	c := ContainerWrap{Container: corev1.Container{
+		Env: []corev1.EnvVar{
+			{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "db"},
+				Key:                  "password",
+			}}},
+		},
+	}}
+
+	listErr := errors.New("forbidden")
+
+	envs, err := resolveEnv(context.Background(), &fakeEnvSources{err: listErr}, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(envs) != 1 || envs[0].Value != "" {
+		t.Errorf("expected single empty value; got=%+v", envs)
+	}
+
+	if _, err := resolveEnv(context.Background(), &fakeEnvSources{err: listErr, failFast: true}, c); err != listErr {
+		t.Errorf("fail fast: expected=%v; got=%v", listErr, err)
+	}
+}
@@ -8,154 +8,262 @@ import (
 	"github.com/kolide/osquery-go/plugin/table"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/palestamp/ksql/pkg/kubeapi"
+	"github.com/palestamp/ksql/pkg/redact"
 )
 
 type EnvVars struct {
 	kc *kubeapi.KubeConfig
+	r  *redact.Redactor
 }
 
-func NewEnvVars(kc *kubeapi.KubeConfig) *EnvVars {
-	return &EnvVars{kc: kc}
+func NewEnvVars(kc *kubeapi.KubeConfig, r *redact.Redactor) *EnvVars {
+	return &EnvVars{kc: kc, r: r}
 }
 func (d *EnvVars) Columns() []table.ColumnDefinition {
-	return []table.ColumnDefinition{
+	columns := []table.ColumnDefinition{
 		table.TextColumn("context"),
 		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
 		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
 		table.TextColumn("image"),
 		table.TextColumn("tag"),
 		table.TextColumn("env_key"),
-		table.TextColumn("env_value"),
+	}
+
+	columns = append(columns, redactedColumns("env_value")...)
+
+	return append(columns,
 		table.TextColumn("env_is_secret"),
 		table.TextColumn("secret_name"),
 		table.TextColumn("secret_key"),
-	}
+		table.TextColumn("source"),
+		table.TextColumn("source_name"),
+		table.TextColumn("source_key"),
+		table.TextColumn("label_selector"),
+	)
 }
 
 func (d *EnvVars) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
 	logger := log.WithField("generate", "env-vars")
 	logQueryContext(logger, queryContext)
 
-	containers, err := listContainers(d.kc, queryContext)
+	selector := labelSelector(queryContext)
 
-	var rows []map[string]string
-	for _, c := range containers {
-		image, tag := splitTag(c.Container.Image)
-
-		for _, e := range c.Container.Env {
-			env := getEnvVar(e)
-
-			rows = append(rows, map[string]string{
-				"context":       c.Context,
-				"namespace":     c.Namespace,
-				"deployment":    c.Deployment,
-				"image":         image,
-				"tag":           tag,
-				"env_key":       env.Name,
-				"env_value":     strings.TrimSpace(env.Value),
-				"env_is_secret": fmt.Sprintf("%t", env.IsSecret),
-				"secret_name":   env.SecretName,
-				"secret_key":    env.SecretKey,
-			})
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// env is resolved by namespace workers, so Secrets and ConfigMaps of
+	// different namespaces are fetched in parallel
+	results := make([][]map[string]string, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		containers, err := namespaceContainers(ctx, d.kc, namespaces[i], queryContext)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range containers {
+			image, tag := splitTag(c.Container.Image)
+
+			envs, err := resolveEnv(ctx, d.kc, c)
+			if err != nil {
+				return err
+			}
+
+			for _, env := range envs {
+				row := map[string]string{
+					"context":        c.Context,
+					"namespace":      c.Namespace,
+					"workload_kind":  c.WorkloadKind,
+					"deployment":     c.Workload,
+					"container":      c.Container.Name,
+					"container_type": c.ContainerType,
+					"image":          image,
+					"tag":            tag,
+					"env_key":        env.Name,
+					"env_is_secret":  fmt.Sprintf("%t", env.IsSecret),
+					"secret_name":    env.SecretName,
+					"secret_key":     env.SecretKey,
+					"source":         env.Source,
+					"source_name":    env.SourceName,
+					"source_key":     env.SourceKey,
+					"label_selector": selector,
+				}
+
+				value := d.r.Redact(strings.TrimSpace(env.Value))
+				results[i] = append(results[i], setRedacted(row, "env_value", value))
+			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return rows, err
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
 }
 
-func listNamespaces(kc *kubeapi.KubeConfig, qc table.QueryContext) ([]NamespaceWrap, error) {
+func listNamespaces(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext) ([]NamespaceWrap, error) {
 	contexts, err := kc.ListContexts()
 	if err != nil {
 		return nil, err
 	}
 
-	var out []NamespaceWrap
-	for _, c := range filterConstraint(contexts, qc.Constraints["context"]) {
-		namespaces, err := kc.ListNamespaces(c)
+	contexts = filterConstraint(contexts, qc.Constraints["context"])
+
+	results := make([][]NamespaceWrap, len(contexts))
+	err = forEach(kc.Concurrency(), len(contexts), func(i int) error {
+		c := contexts[i]
+
+		namespaces, err := kc.ListNamespaces(ctx, c)
 		if err != nil {
-			return nil, err
+			return skipFailed(ctx, kc, err)
 		}
 
 		for _, n := range filterConstraint(namespaces, qc.Constraints["namespace"]) {
-			out = append(out, NamespaceWrap{
+			results[i] = append(results[i], NamespaceWrap{
 				Context:   c,
 				Namespace: n,
 			})
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []NamespaceWrap
+	for _, r := range results {
+		out = append(out, r...)
+	}
+
+	return out, nil
+}
+
+const (
+	containerTypeInit      = "init"
+	containerTypeContainer = "container"
+	containerTypeEphemeral = "ephemeral"
+)
+
+// listContainers returns containers of all namespaces matching constraints,
+// see namespaceContainers.
+func listContainers(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext) ([]ContainerWrap, error) {
+	namespaces, err := listNamespaces(ctx, kc, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]ContainerWrap, len(namespaces))
+	err = forEach(kc.Concurrency(), len(namespaces), func(i int) error {
+		containers, err := namespaceContainers(ctx, kc, namespaces[i], qc)
+		results[i] = containers
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ContainerWrap
+	for _, r := range results {
+		out = append(out, r...)
 	}
 
 	return out, nil
 }
 
-func listContainers(kc *kubeapi.KubeConfig, qc table.QueryContext) ([]ContainerWrap, error) {
-	namespaces, err := listNamespaces(kc, qc)
+// namespaceContainers returns containers of workload pod templates matching
+// constraints on workload kind, deployment, container and container_type.
+// Ephemeral containers exist only on live pods, so they are reported for
+// pods with workload kind "Pod".
+func namespaceContainers(ctx context.Context, kc *kubeapi.KubeConfig, n NamespaceWrap, qc table.QueryContext) ([]ContainerWrap, error) {
+	wantsType := func(t string) bool {
+		return len(filterConstraint([]string{t}, qc.Constraints["container_type"])) != 0
+	}
+
+	workloads, err := listWorkloads(ctx, kc, n, qc, "workload_kind", "deployment")
 	if err != nil {
 		return nil, err
 	}
 
 	var out []ContainerWrap
-	for _, n := range namespaces {
-		deployments, err := kc.ListDeployments(n.Context, n.Namespace)
-		if err != nil {
-			return nil, err
+	add := func(w WorkloadWrap, t string, cn corev1.Container) {
+		if !wantsType(t) || len(filterConstraint([]string{cn.Name}, qc.Constraints["container"])) == 0 {
+			return
 		}
 
-		for _, d := range deployments {
-			if len(filterConstraint([]string{d.Name}, qc.Constraints["deployment"])) == 0 {
-				continue
-			}
+		out = append(out, ContainerWrap{
+			Context:       w.Context,
+			Namespace:     w.Namespace,
+			WorkloadKind:  w.Kind,
+			Workload:      w.Name,
+			ContainerType: t,
+			Template:      w.Template,
+			Container:     cn,
+		})
+	}
 
-			for _, cn := range d.Spec.Template.Spec.Containers {
-				out = append(out, ContainerWrap{
-					Context:    n.Context,
-					Namespace:  n.Namespace,
-					Deployment: d.Name,
-					Container:  cn,
-				})
-			}
+	for _, w := range workloads {
+		for _, cn := range w.Template.Spec.InitContainers {
+			add(w, containerTypeInit, cn)
 		}
 
-		statefulSets, err := kc.ListStatefulSets(n.Context, n.Namespace)
-		if err != nil {
-			return nil, err
+		for _, cn := range w.Template.Spec.Containers {
+			add(w, containerTypeContainer, cn)
 		}
 
-		for _, s := range statefulSets {
-			if len(filterConstraint([]string{s.Name}, qc.Constraints["deployment"])) == 0 {
-				continue
-			}
-
-			for _, cn := range s.Spec.Template.Spec.Containers {
-				out = append(out, ContainerWrap{
-					Context:    n.Context,
-					Namespace:  n.Namespace,
-					Deployment: s.Name,
-					Container:  cn,
-				})
-			}
+		// set for bare pods only, pods managed by workloads are handled below
+		for _, ec := range w.Template.Spec.EphemeralContainers {
+			add(w, containerTypeEphemeral, corev1.Container(ec.EphemeralContainerCommon))
 		}
 	}
 
-	return out, nil
-}
-
-func getEnvVar(env corev1.EnvVar) EnvVar {
-	e := EnvVar{Name: env.Name}
+	if !wantsType(containerTypeEphemeral) || len(filterConstraint([]string{kindPod}, qc.Constraints["workload_kind"])) == 0 {
+		return out, nil
+	}
 
-	if env.Value != "" {
-		e.Value = env.Value
-		return e
+	pods, err := kc.ListPods(ctx, n.Context, n.Namespace, labelSelector(qc))
+	if err != nil {
+		return out, skipFailed(ctx, kc, err)
 	}
 
-	if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
-		e.IsSecret = true
-		e.SecretName = env.ValueFrom.SecretKeyRef.Name
-		e.SecretKey = env.ValueFrom.SecretKeyRef.Key
+	for j := range pods {
+		p := &pods[j]
+		if len(p.Spec.EphemeralContainers) == 0 || metav1.GetControllerOf(p) == nil {
+			continue
+		}
+
+		if len(filterConstraint([]string{p.Name}, qc.Constraints["deployment"])) == 0 {
+			continue
+		}
+
+		w := WorkloadWrap{
+			Context:   n.Context,
+			Namespace: n.Namespace,
+			Kind:      kindPod,
+			Name:      p.Name,
+			Template:  &corev1.PodTemplateSpec{ObjectMeta: p.ObjectMeta, Spec: p.Spec},
+		}
+
+		for _, ec := range p.Spec.EphemeralContainers {
+			add(w, containerTypeEphemeral, corev1.Container(ec.EphemeralContainerCommon))
+		}
 	}
 
-	return e
+	return out, nil
 }
 
 type NamespaceWrap struct {
@@ -164,10 +272,14 @@ type NamespaceWrap struct {
 }
 
 type ContainerWrap struct {
-	Context    string
-	Namespace  string
-	Deployment string
-	Container  corev1.Container
+	Context       string
+	Namespace     string
+	WorkloadKind  string
+	Workload      string
+	ContainerType string
+	// Template is shared with kubeapi cache and must not be modified.
+	Template  *corev1.PodTemplateSpec
+	Container corev1.Container
 }
 
 type EnvVar struct {
@@ -176,4 +288,8 @@ type EnvVar struct {
 	IsSecret   bool
 	SecretName string
 	SecretKey  string
+	// Source is one of envSource* constants.
+	Source     string
+	SourceName string
+	SourceKey  string
 }
@@ -0,0 +1,50 @@
+package tables
+
+import (
+	"context"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Errors struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewErrors(kc *kubeapi.KubeConfig) *Errors {
+	return &Errors{kc: kc}
+}
+
+func (d *Errors) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("resource"),
+		table.TextColumn("namespace"),
+		table.TextColumn("error"),
+		table.BigIntColumn("timestamp"),
+	}
+}
+
+func (d *Errors) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "errors")
+	logQueryContext(logger, queryContext)
+
+	var rows []map[string]string
+	for _, e := range d.kc.Errors() {
+		if len(filterConstraint([]string{e.Context}, queryContext.Constraints["context"])) == 0 {
+			continue
+		}
+
+		rows = append(rows, map[string]string{
+			"context":   e.Context,
+			"resource":  e.Resource,
+			"namespace": e.Namespace,
+			"error":     e.Err.Error(),
+			"timestamp": formatTime(e.Time),
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,153 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Events struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewEvents(kc *kubeapi.KubeConfig) *Events {
+	return &Events{kc: kc}
+}
+
+func (d *Events) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("involved_object_kind"),
+		table.TextColumn("involved_object_name"),
+		table.TextColumn("type"),
+		table.TextColumn("reason"),
+		table.TextColumn("message"),
+		table.IntegerColumn("count"),
+		table.BigIntColumn("first_timestamp"),
+		table.BigIntColumn("last_timestamp"),
+		table.TextColumn("source"),
+	}
+}
+
+// Generate pushes "=" constraints on namespace, type and involved object
+// down to API as field selectors, without namespace constraint events are
+// listed with a single request per context.
+func (d *Events) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "events")
+	logQueryContext(logger, queryContext)
+
+	contexts, err := d.kc.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	contexts = filterConstraint(contexts, queryContext.Constraints["context"])
+
+	var filter kubeapi.EventFilter
+	filter.Type, _ = equalityConstraint(queryContext.Constraints["type"])
+	filter.InvolvedObjectKind, _ = equalityConstraint(queryContext.Constraints["involved_object_kind"])
+	filter.InvolvedObjectName, _ = equalityConstraint(queryContext.Constraints["involved_object_name"])
+
+	namespace, _ := equalityConstraint(queryContext.Constraints["namespace"])
+
+	results := make([][]map[string]string, len(contexts))
+	err = forEach(d.kc.Concurrency(), len(contexts), func(i int) error {
+		c := contexts[i]
+
+		events, err := d.kc.ListEvents(ctx, c, namespace, filter)
+		if err != nil {
+			return skipFailed(ctx, d.kc, err)
+		}
+
+		for _, e := range events {
+			if len(filterConstraint([]string{e.Namespace}, queryContext.Constraints["namespace"])) == 0 {
+				continue
+			}
+
+			results[i] = append(results[i], map[string]string{
+				"context":              c,
+				"namespace":            e.Namespace,
+				"involved_object_kind": e.InvolvedObject.Kind,
+				"involved_object_name": e.InvolvedObject.Name,
+				"type":                 e.Type,
+				"reason":               e.Reason,
+				"message":              e.Message,
+				"count":                strconv.Itoa(int(eventCount(e))),
+				"first_timestamp":      formatTime(eventFirstTimestamp(e)),
+				"last_timestamp":       formatTime(eventLastTimestamp(e)),
+				"source":               eventSource(e),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
+}
+
+// eventCount handles events emitted through events.k8s.io API, which populate
+// series and event time instead of count and timestamps.
+func eventCount(e corev1.Event) int32 {
+	if e.Series != nil {
+		return e.Series.Count
+	}
+
+	if e.Count == 0 {
+		return 1
+	}
+
+	return e.Count
+}
+
+func eventFirstTimestamp(e corev1.Event) time.Time {
+	if !e.FirstTimestamp.IsZero() {
+		return e.FirstTimestamp.Time
+	}
+
+	return e.EventTime.Time
+}
+
+func eventLastTimestamp(e corev1.Event) time.Time {
+	if e.Series != nil {
+		return e.Series.LastObservedTime.Time
+	}
+
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+
+	return e.EventTime.Time
+}
+
+func eventSource(e corev1.Event) string {
+	component := e.Source.Component
+	if component == "" {
+		component = e.ReportingController
+	}
+
+	host := e.Source.Host
+	if host == "" {
+		host = e.ReportingInstance
+	}
+
+	if host == "" {
+		return component
+	}
+
+	return component + "/" + host
+}
@@ -0,0 +1,158 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+type Ingresses struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewIngresses(kc *kubeapi.KubeConfig) *Ingresses {
+	return &Ingresses{kc: kc}
+}
+
+func (d *Ingresses) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("name"),
+		table.TextColumn("ingress_class"),
+		table.TextColumn("host"),
+		table.TextColumn("path"),
+		table.TextColumn("path_type"),
+		table.TextColumn("service"),
+		table.TextColumn("service_port"),
+		table.TextColumn("resource"),
+		table.TextColumn("tls_secret"),
+	}
+}
+
+func (d *Ingresses) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "ingresses")
+	logQueryContext(logger, queryContext)
+
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]map[string]string, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		n := namespaces[i]
+
+		ingresses, err := d.kc.ListIngresses(ctx, n.Context, n.Namespace)
+		if err != nil {
+			return skipFailed(ctx, d.kc, err)
+		}
+
+		for _, ing := range ingresses {
+			if len(filterConstraint([]string{ing.Name}, queryContext.Constraints["name"])) == 0 {
+				continue
+			}
+
+			for _, r := range ingressRows(ing) {
+				r["context"] = n.Context
+				r["namespace"] = n.Namespace
+				results[i] = append(results[i], r)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
+}
+
+// ingressRows returns row per host/path/backend, default backend is reported
+// with empty host and path.
+func ingressRows(ing networkingv1.Ingress) []map[string]string {
+	class := ing.Annotations[ingressClassAnnotation]
+	if ing.Spec.IngressClassName != nil {
+		class = *ing.Spec.IngressClassName
+	}
+
+	row := func(host, path, pathType string, b *networkingv1.IngressBackend) map[string]string {
+		r := map[string]string{
+			"name":          ing.Name,
+			"ingress_class": class,
+			"host":          host,
+			"path":          path,
+			"path_type":     pathType,
+			"tls_secret":    ingressTLSSecret(ing, host),
+		}
+
+		if b != nil && b.Service != nil {
+			r["service"] = b.Service.Name
+			r["service_port"] = b.Service.Port.Name
+			if b.Service.Port.Number != 0 {
+				r["service_port"] = strconv.Itoa(int(b.Service.Port.Number))
+			}
+		}
+
+		if b != nil && b.Resource != nil {
+			r["resource"] = b.Resource.Kind + "/" + b.Resource.Name
+		}
+
+		return r
+	}
+
+	var rows []map[string]string
+	if ing.Spec.DefaultBackend != nil {
+		rows = append(rows, row("", "", "", ing.Spec.DefaultBackend))
+	}
+
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			rows = append(rows, row(rule.Host, "", "", nil))
+			continue
+		}
+
+		for _, p := range rule.HTTP.Paths {
+			var pathType string
+			if p.PathType != nil {
+				pathType = string(*p.PathType)
+			}
+
+			b := p.Backend
+			rows = append(rows, row(rule.Host, p.Path, pathType, &b))
+		}
+	}
+
+	return rows
+}
+
+// ingressTLSSecret returns secret terminating TLS for host, TLS entry without
+// hosts applies to all hosts.
+func ingressTLSSecret(ing networkingv1.Ingress, host string) string {
+	for _, tls := range ing.Spec.TLS {
+		if len(tls.Hosts) == 0 {
+			return tls.SecretName
+		}
+
+		for _, h := range tls.Hosts {
+			if h == host {
+				return tls.SecretName
+			}
+		}
+	}
+
+	return ""
+}
@@ -3,8 +3,10 @@ package tables
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kolide/osquery-go/plugin/table"
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,28 @@ type Table interface {
 	Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error)
 }
 
+// WithQueryTimeout limits duration of t.Generate, API requests still running
+// when deadline is exceeded are cancelled. d <= 0 disables the limit.
+func WithQueryTimeout(t Table, d time.Duration) Table {
+	if d <= 0 {
+		return t
+	}
+
+	return &deadlineTable{Table: t, timeout: d}
+}
+
+type deadlineTable struct {
+	Table
+	timeout time.Duration
+}
+
+func (t *deadlineTable) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	return t.Table.Generate(ctx, queryContext)
+}
+
 func logQueryContext(logger *logrus.Entry, qc table.QueryContext) {
 	var clauses []string
 	for name, column := range qc.Constraints {
@@ -26,11 +50,14 @@ func logQueryContext(logger *logrus.Entry, qc table.QueryContext) {
 	logger.Infof("query: %s", strings.Join(clauses, " and "))
 }
 
+// operatorNotEquals is "!=" operator, it is not defined by osquery-go.
+const operatorNotEquals = table.Operator(68)
+
 func stringifyConstraint(op table.Operator) string {
 	switch op {
 	case table.OperatorEquals:
 		return "="
-	case table.Operator(68):
+	case operatorNotEquals:
 		return "!="
 	case table.OperatorGreaterThan:
 		return ">"
@@ -55,36 +82,249 @@ func stringifyConstraint(op table.Operator) string {
 	return strconv.Itoa(int(op))
 }
 
+// filterConstraint returns elements of in satisfying all constraints of a
+// column, operators are evaluated the same way SQLite does. Constraints which
+// cannot be evaluated (MATCH, invalid regular expressions) keep all elements,
+// osquery applies them to generated rows anyway.
 func filterConstraint(in []string, constraints table.ConstraintList) []string {
-
-	if len(constraints.Constraints) != 1 {
+	if len(constraints.Constraints) == 0 {
 		return in
 	}
 
-	c := constraints.Constraints[0]
+	var matchers []func(string) bool
+	for _, c := range constraints.Constraints {
+		if m := constraintMatcher(constraints.Affinity, c); m != nil {
+			matchers = append(matchers, m)
+		}
+	}
 
 	var out []string
+	for _, el := range in {
+		matched := true
+		for _, m := range matchers {
+			if !m(el) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			out = append(out, el)
+		}
+	}
+
+	return out
+}
+
+// constraintMatcher returns nil if constraint cannot be evaluated.
+func constraintMatcher(affinity table.ColumnType, c table.Constraint) func(string) bool {
+	compare := func(fn func(int) bool) func(string) bool {
+		return func(s string) bool {
+			r, ok := compareValues(affinity, s, c.Expression)
+			return !ok || fn(r)
+		}
+	}
 
 	switch c.Operator {
 	case table.OperatorEquals:
+		return compare(func(r int) bool { return r == 0 })
+	case operatorNotEquals:
+		return compare(func(r int) bool { return r != 0 })
+	case table.OperatorGreaterThan:
+		return compare(func(r int) bool { return r > 0 })
+	case table.OperatorGreaterThanOrEquals:
+		return compare(func(r int) bool { return r >= 0 })
+	case table.OperatorLessThan:
+		return compare(func(r int) bool { return r < 0 })
+	case table.OperatorLessThanOrEquals:
+		return compare(func(r int) bool { return r <= 0 })
+	case table.OperatorLike:
+		pattern := []rune(c.Expression)
+		return func(s string) bool { return patternMatch(pattern, []rune(s), likePattern) }
+	case table.OperatorGlob:
+		pattern := []rune(c.Expression)
+		return func(s string) bool { return patternMatch(pattern, []rune(s), globPattern) }
+	case table.OperatorRegexp:
+		re, err := regexp.Compile(c.Expression)
+		if err != nil {
+			return nil
+		}
+		return re.MatchString
+	}
+
+	return nil
+}
+
+// compareValues compares numerically for numeric affinity and bytewise
+// otherwise, as SQLite BINARY collation does. Values which cannot be
+// compared are reported with false.
+func compareValues(affinity table.ColumnType, a, b string) (int, bool) {
+	switch affinity {
+	case table.ColumnTypeInteger, table.ColumnTypeBigInt, table.ColumnTypeDouble:
+		x, err := strconv.ParseFloat(a, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		y, err := strconv.ParseFloat(b, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	return strings.Compare(a, b), true
+}
+
+type patternInfo struct {
+	matchAll rune
+	matchOne rune
+	// matchSet enables [...] character classes.
+	matchSet bool
+	// noCase folds ASCII letters only, same as SQLite built without ICU.
+	noCase bool
+}
+
+var (
+	likePattern = patternInfo{matchAll: '%', matchOne: '_', noCase: true}
+	globPattern = patternInfo{matchAll: '*', matchOne: '?', matchSet: true}
+)
+
+// patternMatch reports whether s matches LIKE or GLOB pattern p.
+func patternMatch(p, s []rune, info patternInfo) bool {
+	for len(p) > 0 {
+		switch c := p[0]; {
+		case c == info.matchAll:
+			for len(p) > 0 && p[0] == info.matchAll {
+				p = p[1:]
+			}
+
+			if len(p) == 0 {
+				return true
+			}
+
+			for i := range s {
+				if patternMatch(p, s[i:], info) {
+					return true
+				}
+			}
 
-		for _, el := range in {
-			if el == c.Expression {
-				out = append(out, el)
+			return false
+
+		case c == info.matchOne:
+			if len(s) == 0 {
+				return false
 			}
+
+			p, s = p[1:], s[1:]
+
+		case c == '[' && info.matchSet:
+			if len(s) == 0 {
+				return false
+			}
+
+			n, ok := matchSet(p, s[0])
+			if !ok {
+				return false
+			}
+
+			p, s = p[n:], s[1:]
+
+		default:
+			if len(s) == 0 || !runeEqual(c, s[0], info.noCase) {
+				return false
+			}
+
+			p, s = p[1:], s[1:]
 		}
+	}
+
+	return len(s) == 0
+}
+
+// matchSet matches r against GLOB character class at the beginning of p and
+// returns length of the class. "]" right after "[" or "[^" is literal,
+// unterminated class never matches.
+func matchSet(p []rune, r rune) (int, bool) {
+	i := 1
 
-	case table.Operator(68):
+	invert := false
+	if i < len(p) && p[i] == '^' {
+		invert = true
+		i++
+	}
+
+	seen := false
+	if i < len(p) && p[i] == ']' {
+		seen = r == ']'
+		i++
+	}
 
-		for _, el := range in {
-			if el != c.Expression {
-				out = append(out, el)
+	var prior rune
+	for i < len(p) && p[i] != ']' {
+		if p[i] == '-' && prior != 0 && i+1 < len(p) && p[i+1] != ']' {
+			if r >= prior && r <= p[i+1] {
+				seen = true
 			}
+			prior = 0
+			i += 2
+			continue
 		}
 
-	default:
-		return in
+		if r == p[i] {
+			seen = true
+		}
+		prior = p[i]
+		i++
 	}
 
-	return out
+	if i == len(p) {
+		return 0, false
+	}
+
+	return i + 1, seen != invert
+}
+
+func runeEqual(a, b rune, noCase bool) bool {
+	if noCase {
+		return foldASCII(a) == foldASCII(b)
+	}
+
+	return a == b
+}
+
+func foldASCII(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + 'a' - 'A'
+	}
+
+	return r
+}
+
+// equalityConstraint returns expression of the first "=" constraint, which
+// can be pushed down to API as all constraints of a column must hold.
+func equalityConstraint(constraints table.ConstraintList) (string, bool) {
+	for _, c := range constraints.Constraints {
+		if c.Operator == table.OperatorEquals {
+			return c.Expression, true
+		}
+	}
+
+	return "", false
+}
+
+// labelSelector returns "label_selector" column constraint which is passed
+// to API server as is, rows of tables exposing the column must echo it back
+// so osquery does not filter them out.
+func labelSelector(qc table.QueryContext) string {
+	selector, _ := equalityConstraint(qc.Constraints["label_selector"])
+	return selector
 }
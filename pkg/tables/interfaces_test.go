@@ -0,0 +1,176 @@
+package tables
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kolide/osquery-go/plugin/table"
+)
+
+func TestPatternMatchLike(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"prod-%", "prod-eu", true},
+		{"prod-%", "prod-", true},
+		{"prod-%", "staging-eu", false},
+		{"PROD-%", "prod-eu", true},
+		{"%-eu", "prod-eu", true},
+		{"%-eu", "prod-eu-1", false},
+		{"prod-__", "prod-eu", true},
+		{"prod-_", "prod-eu", false},
+		{"%", "", true},
+		{"_", "", false},
+		{"%o%", "foo", true},
+		{"%%%x", "abc", false},
+		{"Ä%", "ä", false},
+		{"_", "ä", true},
+		{"a*", "a*", true},
+		{"a*", "ab", false},
+		{"[a]", "a", false},
+	}
+
+	for _, tc := range cases {
+		if got := patternMatch([]rune(tc.pattern), []rune(tc.s), likePattern); got != tc.expected {
+			t.Errorf("%q LIKE %q: expected=%t; got=%t", tc.s, tc.pattern, tc.expected, got)
+		}
+	}
+}
+
+func TestPatternMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"prod-*", "prod-eu", true},
+		{"PROD-*", "prod-eu", false},
+		{"prod-??", "prod-eu", true},
+		{"prod-?", "prod-eu", false},
+		{"*", "", true},
+		{"a%", "a%", true},
+		{"a%", "ab", false},
+		{"kube-[a-c]*", "kube-system", false},
+		{"kube-[p-t]*", "kube-system", true},
+		{"kube-[^p-t]*", "kube-system", false},
+		{"kube-[^p-t]*", "kube-node-lease", true},
+		{"[]]", "]", true},
+		{"[^]]", "]", false},
+		{"[a-]", "-", true},
+		{"[abc", "a", false},
+		{"*[0-9]", "node-1", true},
+		{"*[0-9]", "node-a", false},
+	}
+
+	for _, tc := range cases {
+		if got := patternMatch([]rune(tc.pattern), []rune(tc.s), globPattern); got != tc.expected {
+			t.Errorf("%q GLOB %q: expected=%t; got=%t", tc.s, tc.pattern, tc.expected, got)
+		}
+	}
+}
+
+func TestFilterConstraint(t *testing.T) {
+	in := []string{"default", "kube-public", "kube-system", "prod-eu", "prod-us"}
+
+	cases := []struct {
+		name        string
+		constraints table.ConstraintList
+		expected    []string
+	}{
+		{
+			name:     "no constraints",
+			expected: in,
+		},
+		{
+			name: "equals",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorEquals, Expression: "prod-eu"},
+			}},
+			expected: []string{"prod-eu"},
+		},
+		{
+			name: "not equals",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: operatorNotEquals, Expression: "default"},
+			}},
+			expected: []string{"kube-public", "kube-system", "prod-eu", "prod-us"},
+		},
+		{
+			name: "like and not equals",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorLike, Expression: "PROD-%"},
+				{Operator: operatorNotEquals, Expression: "prod-us"},
+			}},
+			expected: []string{"prod-eu"},
+		},
+		{
+			name: "glob",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorGlob, Expression: "kube-*"},
+			}},
+			expected: []string{"kube-public", "kube-system"},
+		},
+		{
+			name: "regexp",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorRegexp, Expression: "-(eu|us)$"},
+			}},
+			expected: []string{"prod-eu", "prod-us"},
+		},
+		{
+			name: "invalid regexp",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorRegexp, Expression: "("},
+			}},
+			expected: in,
+		},
+		{
+			name: "text range",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorGreaterThanOrEquals, Expression: "kube-system"},
+				{Operator: table.OperatorLessThan, Expression: "prod-us"},
+			}},
+			expected: []string{"kube-system", "prod-eu"},
+		},
+		{
+			name: "contradiction",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorEquals, Expression: "default"},
+				{Operator: table.OperatorEquals, Expression: "prod-eu"},
+			}},
+			expected: nil,
+		},
+		{
+			name: "match is not evaluated",
+			constraints: table.ConstraintList{Constraints: []table.Constraint{
+				{Operator: table.OperatorMatch, Expression: "prod"},
+			}},
+			expected: in,
+		},
+	}
+
+	for _, tc := range cases {
+		if got := filterConstraint(in, tc.constraints); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected=%q; got=%q", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestFilterConstraintNumeric(t *testing.T) {
+	in := []string{"2", "10", "100"}
+
+	constraints := table.ConstraintList{
+		Affinity: table.ColumnTypeInteger,
+		Constraints: []table.Constraint{
+			{Operator: table.OperatorGreaterThan, Expression: "5"},
+			{Operator: table.OperatorLessThanOrEquals, Expression: "100"},
+		},
+	}
+
+	expected := []string{"10", "100"}
+	if got := filterConstraint(in, constraints); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected=%q; got=%q", expected, got)
+	}
+}
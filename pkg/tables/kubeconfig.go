@@ -0,0 +1,88 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type KubeClusters struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewKubeClusters(kc *kubeapi.KubeConfig) *KubeClusters {
+	return &KubeClusters{kc: kc}
+}
+
+func (d *KubeClusters) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("name"),
+		table.TextColumn("server"),
+		table.TextColumn("has_ca"),
+		table.TextColumn("insecure_skip_tls_verify"),
+	}
+}
+
+func (d *KubeClusters) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "kube-clusters")
+	logQueryContext(logger, queryContext)
+
+	clusters, err := d.kc.ListClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, c := range clusters {
+		rows = append(rows, map[string]string{
+			"name":                     c.Name,
+			"server":                   c.Server,
+			"has_ca":                   fmt.Sprintf("%t", c.HasCA),
+			"insecure_skip_tls_verify": fmt.Sprintf("%t", c.InsecureSkipTLSVerify),
+		})
+	}
+
+	return rows, nil
+}
+
+type KubeUsers struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewKubeUsers(kc *kubeapi.KubeConfig) *KubeUsers {
+	return &KubeUsers{kc: kc}
+}
+
+func (d *KubeUsers) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("name"),
+		table.TextColumn("auth_type"),
+		table.TextColumn("provider"),
+	}
+}
+
+func (d *KubeUsers) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "kube-users")
+	logQueryContext(logger, queryContext)
+
+	users, err := d.kc.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, u := range users {
+		rows = append(rows, map[string]string{
+			"name":      u.Name,
+			"auth_type": strings.Join(u.AuthTypes, ","),
+			"provider":  u.Provider,
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,290 @@
+package tables
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+const (
+	kindNamespace = "Namespace"
+	kindNode      = "Node"
+	kindSecret    = "Secret"
+	kindConfigMap = "ConfigMap"
+	kindService   = "Service"
+	kindIngress   = "Ingress"
+)
+
+var metadataColumns = []table.ColumnDefinition{
+	table.TextColumn("context"),
+	table.TextColumn("namespace"),
+	table.TextColumn("kind"),
+	table.TextColumn("name"),
+	table.TextColumn("key"),
+	table.TextColumn("value"),
+}
+
+// Labels lists labels of every object kind fetched by other tables.
+type Labels struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewLabels(kc *kubeapi.KubeConfig) *Labels {
+	return &Labels{kc: kc}
+}
+
+func (d *Labels) Columns() []table.ColumnDefinition {
+	return metadataColumns
+}
+
+func (d *Labels) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "labels")
+	logQueryContext(logger, queryContext)
+
+	return metadataRows(ctx, d.kc, queryContext, func(m metav1.ObjectMeta) map[string]string {
+		return m.Labels
+	})
+}
+
+// Annotations lists annotations of every object kind fetched by other tables.
+type Annotations struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewAnnotations(kc *kubeapi.KubeConfig) *Annotations {
+	return &Annotations{kc: kc}
+}
+
+func (d *Annotations) Columns() []table.ColumnDefinition {
+	return metadataColumns
+}
+
+func (d *Annotations) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "annotations")
+	logQueryContext(logger, queryContext)
+
+	return metadataRows(ctx, d.kc, queryContext, func(m metav1.ObjectMeta) map[string]string {
+		return m.Annotations
+	})
+}
+
+func metadataRows(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext, field func(metav1.ObjectMeta) map[string]string) ([]map[string]string, error) {
+	objects, err := listObjectMeta(ctx, kc, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, o := range objects {
+		m := field(o.Meta)
+
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range filterConstraint(keys, qc.Constraints["key"]) {
+			rows = append(rows, map[string]string{
+				"context":   o.Context,
+				"namespace": o.Namespace,
+				"kind":      o.Kind,
+				"name":      o.Meta.Name,
+				"key":       k,
+				"value":     m[k],
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+type ObjectMetaWrap struct {
+	Context   string
+	Namespace string
+	Kind      string
+	Meta      metav1.ObjectMeta
+}
+
+// objectLister returns slice of API objects embedding metav1.ObjectMeta.
+type objectLister func(ctx context.Context, kc *kubeapi.KubeConfig, k8sContext, namespace string) (interface{}, error)
+
+// namespacedKinds lists kinds in the order rows are reported.
+var namespacedKinds = []struct {
+	kind string
+	list objectLister
+}{
+	{kindDeployment, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListDeployments(ctx, c, n, "")
+	}},
+	{kindStatefulSet, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListStatefulSets(ctx, c, n, "")
+	}},
+	{kindDaemonSet, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListDaemonSets(ctx, c, n, "")
+	}},
+	{kindReplicaSet, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListReplicaSets(ctx, c, n, "")
+	}},
+	{kindCronJob, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListCronJobs(ctx, c, n, "")
+	}},
+	{kindJob, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListJobs(ctx, c, n, "")
+	}},
+	{kindPod, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListPods(ctx, c, n, "")
+	}},
+	{kindService, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListServices(ctx, c, n)
+	}},
+	{kindIngress, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListIngresses(ctx, c, n)
+	}},
+	{kindConfigMap, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListConfigMaps(ctx, c, n)
+	}},
+	{kindSecret, func(ctx context.Context, kc *kubeapi.KubeConfig, c, n string) (interface{}, error) {
+		return kc.ListSecrets(ctx, c, n, "")
+	}},
+}
+
+// objectMetas extracts metadata from slice of API objects.
+func objectMetas(items interface{}) []metav1.ObjectMeta {
+	v := reflect.ValueOf(items)
+
+	out := make([]metav1.ObjectMeta, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		out = append(out, v.Index(i).FieldByName("ObjectMeta").Interface().(metav1.ObjectMeta))
+	}
+
+	return out
+}
+
+// listObjectMeta returns metadata of objects matching constraints on context,
+// namespace, kind and name. Cluster scoped objects (namespaces and nodes) are
+// reported with empty namespace. Only requested kinds are fetched.
+func listObjectMeta(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext) ([]ObjectMetaWrap, error) {
+	wants := func(kind string) bool {
+		return len(filterConstraint([]string{kind}, qc.Constraints["kind"])) != 0
+	}
+
+	match := func(m metav1.ObjectMeta) bool {
+		return len(filterConstraint([]string{m.Name}, qc.Constraints["name"])) != 0
+	}
+
+	var out []ObjectMetaWrap
+
+	clusterScoped := len(filterConstraint([]string{""}, qc.Constraints["namespace"])) != 0 &&
+		(wants(kindNamespace) || wants(kindNode))
+
+	if clusterScoped {
+		contexts, err := kc.ListContexts()
+		if err != nil {
+			return nil, err
+		}
+
+		contexts = filterConstraint(contexts, qc.Constraints["context"])
+
+		results := make([][]ObjectMetaWrap, len(contexts))
+		err = forEach(kc.Concurrency(), len(contexts), func(i int) error {
+			c := contexts[i]
+
+			if wants(kindNamespace) {
+				namespaces, err := kc.ListNamespaceObjects(ctx, c)
+				if err := skipFailed(ctx, kc, err); err != nil {
+					return err
+				}
+
+				for _, n := range namespaces {
+					if match(n.ObjectMeta) {
+						results[i] = append(results[i], ObjectMetaWrap{Context: c, Kind: kindNamespace, Meta: n.ObjectMeta})
+					}
+				}
+			}
+
+			if wants(kindNode) {
+				nodes, err := kc.ListNodes(ctx, c)
+				if err := skipFailed(ctx, kc, err); err != nil {
+					return err
+				}
+
+				for _, n := range nodes {
+					if match(n.ObjectMeta) {
+						results[i] = append(results[i], ObjectMetaWrap{Context: c, Kind: kindNode, Meta: n.ObjectMeta})
+					}
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range results {
+			out = append(out, r...)
+		}
+	}
+
+	var kinds []int
+	for i, k := range namespacedKinds {
+		if wants(k.kind) {
+			kinds = append(kinds, i)
+		}
+	}
+
+	if len(kinds) == 0 {
+		return out, nil
+	}
+
+	namespaces, err := listNamespaces(ctx, kc, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]ObjectMetaWrap, len(namespaces))
+	err = forEach(kc.Concurrency(), len(namespaces), func(i int) error {
+		n := namespaces[i]
+
+		for _, k := range kinds {
+			kind := namespacedKinds[k]
+
+			items, err := kind.list(ctx, kc, n.Context, n.Namespace)
+			if err != nil {
+				if err := skipFailed(ctx, kc, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, m := range objectMetas(items) {
+				if match(m) {
+					results[i] = append(results[i], ObjectMetaWrap{
+						Context:   n.Context,
+						Namespace: n.Namespace,
+						Kind:      kind.kind,
+						Meta:      m,
+					})
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		out = append(out, r...)
+	}
+
+	return out, nil
+}
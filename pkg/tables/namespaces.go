@@ -32,20 +32,33 @@ func (d *Namespaces) Generate(ctx context.Context, queryContext table.QueryConte
 		return nil, err
 	}
 
-	var rows []map[string]string
-	for _, c := range filterConstraint(contexts, queryContext.Constraints["context"]) {
+	contexts = filterConstraint(contexts, queryContext.Constraints["context"])
+
+	results := make([][]map[string]string, len(contexts))
+	err = forEach(d.kc.Concurrency(), len(contexts), func(i int) error {
+		c := contexts[i]
 
-		namespaces, err := d.kc.ListNamespaces(c)
+		namespaces, err := d.kc.ListNamespaces(ctx, c)
 		if err != nil {
-			return nil, err
+			return skipFailed(ctx, d.kc, err)
 		}
 
 		for _, n := range namespaces {
-			rows = append(rows, map[string]string{
+			results[i] = append(results[i], map[string]string{
 				"context": c,
 				"name":    n,
 			})
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
 	}
 
 	return rows, nil
@@ -0,0 +1,180 @@
+package tables
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+const (
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	legacyNodeRoleLabel = "kubernetes.io/role"
+)
+
+var (
+	zoneLabels   = []string{corev1.LabelZoneFailureDomainStable, corev1.LabelZoneFailureDomain}
+	regionLabels = []string{corev1.LabelZoneRegionStable, corev1.LabelZoneRegion}
+)
+
+type Nodes struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewNodes(kc *kubeapi.KubeConfig) *Nodes {
+	return &Nodes{kc: kc}
+}
+
+func (d *Nodes) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("name"),
+		table.TextColumn("roles"),
+		table.TextColumn("kubelet_version"),
+		table.TextColumn("os_image"),
+		table.TextColumn("kernel_version"),
+		table.TextColumn("container_runtime"),
+		table.BigIntColumn("cpu_capacity"),
+		table.BigIntColumn("memory_capacity"),
+		table.BigIntColumn("pods_capacity"),
+		table.BigIntColumn("cpu_allocatable"),
+		table.BigIntColumn("memory_allocatable"),
+		table.BigIntColumn("pods_allocatable"),
+		table.TextColumn("ready"),
+		table.TextColumn("memory_pressure"),
+		table.TextColumn("disk_pressure"),
+		table.IntegerColumn("taints"),
+		table.TextColumn("zone"),
+		table.TextColumn("region"),
+		table.BigIntColumn("created_at"),
+	}
+}
+
+// Generate reports cpu in millicores and memory in bytes.
+func (d *Nodes) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "nodes")
+	logQueryContext(logger, queryContext)
+
+	contexts, err := d.kc.ListContexts()
+	if err != nil {
+		return nil, err
+	}
+
+	contexts = filterConstraint(contexts, queryContext.Constraints["context"])
+
+	results := make([][]map[string]string, len(contexts))
+	err = forEach(d.kc.Concurrency(), len(contexts), func(i int) error {
+		c := contexts[i]
+
+		nodes, err := d.kc.ListNodes(ctx, c)
+		if err != nil {
+			return skipFailed(ctx, d.kc, err)
+		}
+
+		for _, n := range nodes {
+			if len(filterConstraint([]string{n.Name}, queryContext.Constraints["name"])) == 0 {
+				continue
+			}
+
+			info := n.Status.NodeInfo
+			results[i] = append(results[i], map[string]string{
+				"context":            c,
+				"name":               n.Name,
+				"roles":              strings.Join(nodeRoles(n), ","),
+				"kubelet_version":    info.KubeletVersion,
+				"os_image":           info.OSImage,
+				"kernel_version":     info.KernelVersion,
+				"container_runtime":  info.ContainerRuntimeVersion,
+				"cpu_capacity":       milliValue(n.Status.Capacity, corev1.ResourceCPU),
+				"memory_capacity":    value(n.Status.Capacity, corev1.ResourceMemory),
+				"pods_capacity":      value(n.Status.Capacity, corev1.ResourcePods),
+				"cpu_allocatable":    milliValue(n.Status.Allocatable, corev1.ResourceCPU),
+				"memory_allocatable": value(n.Status.Allocatable, corev1.ResourceMemory),
+				"pods_allocatable":   value(n.Status.Allocatable, corev1.ResourcePods),
+				"ready":              nodeCondition(n, corev1.NodeReady),
+				"memory_pressure":    nodeCondition(n, corev1.NodeMemoryPressure),
+				"disk_pressure":      nodeCondition(n, corev1.NodeDiskPressure),
+				"taints":             strconv.Itoa(len(n.Spec.Taints)),
+				"zone":               firstLabel(n.Labels, zoneLabels),
+				"region":             firstLabel(n.Labels, regionLabels),
+				"created_at":         formatTime(n.CreationTimestamp.Time),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
+}
+
+func nodeRoles(n corev1.Node) []string {
+	var roles []string
+	for k, v := range n.Labels {
+		switch {
+		case strings.HasPrefix(k, nodeRoleLabelPrefix):
+			if role := strings.TrimPrefix(k, nodeRoleLabelPrefix); role != "" {
+				roles = append(roles, role)
+			}
+		case k == legacyNodeRoleLabel && v != "":
+			roles = append(roles, v)
+		}
+	}
+
+	sort.Strings(roles)
+
+	return roles
+}
+
+// nodeCondition returns status of condition: "True", "False", "Unknown" or
+// empty string if node does not report it.
+func nodeCondition(n corev1.Node, t corev1.NodeConditionType) string {
+	for _, c := range n.Status.Conditions {
+		if c.Type == t {
+			return string(c.Status)
+		}
+	}
+
+	return ""
+}
+
+func firstLabel(labels map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v, ok := labels[k]; ok {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func milliValue(list corev1.ResourceList, name corev1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatInt(q.MilliValue(), 10)
+}
+
+func value(list corev1.ResourceList, name corev1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatInt(q.Value(), 10)
+}
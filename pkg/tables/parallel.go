@@ -0,0 +1,79 @@
+package tables
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// forEach calls fn for every index in [0, n) running at most limit calls
+// concurrently. Callers are expected to store results by index, so output
+// order does not depend on scheduling. The first error is returned and no
+// new calls are started after it, calls already running are awaited.
+func forEach(limit, n int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return firstErr != nil
+	}
+
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		if failed() {
+			break
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// requestPolicy is implemented by kubeapi.KubeConfig.
+type requestPolicy interface {
+	FailFast() bool
+}
+
+// skipFailed drops request error unless kc is configured to fail fast,
+// so unreachable contexts do not blank out results of the others.
+// Failed requests are recorded by kubeapi and exposed in k8s_errors table.
+// Errors are kept once ctx is done, as remaining requests would fail too.
+func skipFailed(ctx context.Context, kc requestPolicy, err error) error {
+	if err == nil || kc.FailFast() || ctx.Err() != nil {
+		return err
+	}
+
+	log.WithError(err).Warn("Skipping failed request")
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package tables
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEachStopsAfterError(t *testing.T) {
+	var calls int32
+	err := forEach(1, 10, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if calls != 3 {
+		t.Errorf("expected=3 calls; got=%d", calls)
+	}
+}
+
+func TestForEachCallsAll(t *testing.T) {
+	results := make([]int, 100)
+	err := forEach(8, len(results), func(i int) error {
+		results[i] = i
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, r := range results {
+		if r != i {
+			t.Fatalf("results[%d]: expected=%d; got=%d", i, i, r)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package tables
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Pods struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewPods(kc *kubeapi.KubeConfig) *Pods {
+	return &Pods{kc: kc}
+}
+
+func (d *Pods) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("name"),
+		table.TextColumn("phase"),
+		table.TextColumn("node"),
+		table.TextColumn("pod_ip"),
+		table.TextColumn("host_ip"),
+		table.TextColumn("qos_class"),
+		table.BigIntColumn("start_time"),
+		table.TextColumn("owner_kind"),
+		table.TextColumn("owner_name"),
+		table.IntegerColumn("restarts"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *Pods) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "pods")
+	logQueryContext(logger, queryContext)
+
+	pods, err := listPods(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labelSelector(queryContext)
+
+	var rows []map[string]string
+	for _, p := range pods {
+		var ownerKind, ownerName string
+		if owner := metav1.GetControllerOf(&p.Pod); owner != nil {
+			ownerKind, ownerName = owner.Kind, owner.Name
+		}
+
+		var startTime string
+		if p.Pod.Status.StartTime != nil {
+			startTime = formatTime(p.Pod.Status.StartTime.Time)
+		}
+
+		rows = append(rows, map[string]string{
+			"context":        p.Context,
+			"namespace":      p.Namespace,
+			"name":           p.Pod.Name,
+			"phase":          string(p.Pod.Status.Phase),
+			"node":           p.Pod.Spec.NodeName,
+			"pod_ip":         p.Pod.Status.PodIP,
+			"host_ip":        p.Pod.Status.HostIP,
+			"qos_class":      string(p.Pod.Status.QOSClass),
+			"start_time":     startTime,
+			"owner_kind":     ownerKind,
+			"owner_name":     ownerName,
+			"restarts":       strconv.Itoa(int(podRestarts(p.Pod))),
+			"label_selector": selector,
+		})
+	}
+
+	return rows, nil
+}
+
+// listPods returns pods matching constraints on context, namespace, name
+// and label selector.
+func listPods(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext) ([]PodWrap, error) {
+	namespaces, err := listNamespaces(ctx, kc, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labelSelector(qc)
+
+	results := make([][]PodWrap, len(namespaces))
+	err = forEach(kc.Concurrency(), len(namespaces), func(i int) error {
+		n := namespaces[i]
+
+		pods, err := kc.ListPods(ctx, n.Context, n.Namespace, selector)
+		if err != nil {
+			return skipFailed(ctx, kc, err)
+		}
+
+		for _, p := range pods {
+			if len(filterConstraint([]string{p.Name}, qc.Constraints["name"])) == 0 {
+				continue
+			}
+
+			results[i] = append(results[i], PodWrap{
+				Context:   n.Context,
+				Namespace: n.Namespace,
+				Pod:       p,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []PodWrap
+	for _, r := range results {
+		out = append(out, r...)
+	}
+
+	return out, nil
+}
+
+func podRestarts(p corev1.Pod) int32 {
+	var total int32
+	for _, s := range p.Status.InitContainerStatuses {
+		total += s.RestartCount
+	}
+
+	for _, s := range p.Status.ContainerStatuses {
+		total += s.RestartCount
+	}
+
+	return total
+}
+
+type PodWrap struct {
+	Context   string
+	Namespace string
+	Pod       corev1.Pod
+}
@@ -0,0 +1,28 @@
+package tables
+
+import (
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+
+	"github.com/palestamp/ksql/pkg/redact"
+)
+
+// redactedColumns defines value column followed by its hash, length and fingerprint.
+func redactedColumns(name string) []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn(name),
+		table.TextColumn(name + "_hash"),
+		table.IntegerColumn(name + "_length"),
+		table.TextColumn(name + "_fingerprint"),
+	}
+}
+
+func setRedacted(row map[string]string, name string, v redact.Value) map[string]string {
+	row[name] = v.Value
+	row[name+"_hash"] = v.Hash
+	row[name+"_length"] = strconv.Itoa(v.Length)
+	row[name+"_fingerprint"] = v.Fingerprint
+
+	return row
+}
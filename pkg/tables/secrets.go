@@ -2,56 +2,87 @@ package tables
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/kolide/osquery-go/plugin/table"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/palestamp/ksql/pkg/kubeapi"
+	"github.com/palestamp/ksql/pkg/redact"
 )
 
 type Secrets struct {
 	kc *kubeapi.KubeConfig
+	r  *redact.Redactor
 }
 
-func NewSecrets(kc *kubeapi.KubeConfig) *Secrets {
-	return &Secrets{kc: kc}
+func NewSecrets(kc *kubeapi.KubeConfig, r *redact.Redactor) *Secrets {
+	return &Secrets{kc: kc, r: r}
 }
 func (d *Secrets) Columns() []table.ColumnDefinition {
-	return []table.ColumnDefinition{
+	return append([]table.ColumnDefinition{
 		table.TextColumn("context"),
 		table.TextColumn("namespace"),
 		table.TextColumn("name"),
 		table.TextColumn("key"),
-		table.TextColumn("data"),
-	}
+		table.TextColumn("label_selector"),
+	}, redactedColumns("data")...)
 }
 
 func (d *Secrets) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
 	logger := log.WithField("generate", "secrets")
 	logQueryContext(logger, queryContext)
 
-	namespaces, err := listNamespaces(d.kc, queryContext)
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labelSelector(queryContext)
 
-	var rows []map[string]string
-	for _, c := range namespaces {
-		secrets, err := d.kc.ListSecrets(c.Context, c.Namespace)
+	results := make([][]map[string]string, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		c := namespaces[i]
+
+		secrets, err := d.kc.ListSecrets(ctx, c.Context, c.Namespace, selector)
 		if err != nil {
-			return nil, err
+			return skipFailed(ctx, d.kc, err)
 		}
 
 		for _, s := range secrets {
-			for k, d := range s.Data {
-				rows = append(rows, map[string]string{
-					"context":   c.Context,
-					"namespace": c.Namespace,
-					"name":      s.Name,
-					"key":       k,
-					"data":      strings.TrimSpace(string(d)),
-				})
+			for _, k := range sortedKeys(s.Data) {
+				row := map[string]string{
+					"context":        c.Context,
+					"namespace":      c.Namespace,
+					"name":           s.Name,
+					"key":            k,
+					"label_selector": selector,
+				}
+
+				data := d.r.Redact(strings.TrimSpace(string(s.Data[k])))
+				results[i] = append(results[i], setRedacted(row, "data", data))
 			}
 		}
+
+		return nil
+	})
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
 	}
 
 	return rows, err
 }
+
+func sortedKeys(m map[string][]byte) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,186 @@
+package tables
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Services struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewServices(kc *kubeapi.KubeConfig) *Services {
+	return &Services{kc: kc}
+}
+
+func (d *Services) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("name"),
+		table.TextColumn("type"),
+		table.TextColumn("cluster_ip"),
+		table.TextColumn("external_ips"),
+		table.TextColumn("load_balancer_ingress"),
+		table.TextColumn("selector"),
+		table.TextColumn("session_affinity"),
+	}
+}
+
+func (d *Services) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "services")
+	logQueryContext(logger, queryContext)
+
+	services, err := listServices(ctx, d.kc, queryContext, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, s := range services {
+		var ingress []string
+		for _, i := range s.Service.Status.LoadBalancer.Ingress {
+			if i.IP != "" {
+				ingress = append(ingress, i.IP)
+			} else {
+				ingress = append(ingress, i.Hostname)
+			}
+		}
+
+		rows = append(rows, map[string]string{
+			"context":               s.Context,
+			"namespace":             s.Namespace,
+			"name":                  s.Service.Name,
+			"type":                  string(s.Service.Spec.Type),
+			"cluster_ip":            s.Service.Spec.ClusterIP,
+			"external_ips":          strings.Join(s.Service.Spec.ExternalIPs, ","),
+			"load_balancer_ingress": strings.Join(ingress, ","),
+			"selector":              formatLabels(s.Service.Spec.Selector),
+			"session_affinity":      string(s.Service.Spec.SessionAffinity),
+		})
+	}
+
+	return rows, nil
+}
+
+type ServicePorts struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewServicePorts(kc *kubeapi.KubeConfig) *ServicePorts {
+	return &ServicePorts{kc: kc}
+}
+
+func (d *ServicePorts) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("service"),
+		table.TextColumn("name"),
+		table.TextColumn("protocol"),
+		table.IntegerColumn("port"),
+		table.TextColumn("target_port"),
+		table.IntegerColumn("node_port"),
+	}
+}
+
+func (d *ServicePorts) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "service-ports")
+	logQueryContext(logger, queryContext)
+
+	services, err := listServices(ctx, d.kc, queryContext, "service")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, s := range services {
+		for _, p := range s.Service.Spec.Ports {
+			var nodePort string
+			if p.NodePort != 0 {
+				nodePort = strconv.Itoa(int(p.NodePort))
+			}
+
+			rows = append(rows, map[string]string{
+				"context":     s.Context,
+				"namespace":   s.Namespace,
+				"service":     s.Service.Name,
+				"name":        p.Name,
+				"protocol":    string(p.Protocol),
+				"port":        strconv.Itoa(int(p.Port)),
+				"target_port": p.TargetPort.String(),
+				"node_port":   nodePort,
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+// listServices returns services matching constraints on context, namespace
+// and nameColumn.
+func listServices(ctx context.Context, kc *kubeapi.KubeConfig, qc table.QueryContext, nameColumn string) ([]ServiceWrap, error) {
+	namespaces, err := listNamespaces(ctx, kc, qc)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]ServiceWrap, len(namespaces))
+	err = forEach(kc.Concurrency(), len(namespaces), func(i int) error {
+		n := namespaces[i]
+
+		services, err := kc.ListServices(ctx, n.Context, n.Namespace)
+		if err != nil {
+			return skipFailed(ctx, kc, err)
+		}
+
+		for _, s := range services {
+			if len(filterConstraint([]string{s.Name}, qc.Constraints[nameColumn])) == 0 {
+				continue
+			}
+
+			results[i] = append(results[i], ServiceWrap{
+				Context:   n.Context,
+				Namespace: n.Namespace,
+				Service:   s,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ServiceWrap
+	for _, r := range results {
+		out = append(out, r...)
+	}
+
+	return out, nil
+}
+
+// formatLabels formats map as sorted "k1=v1,k2=v2" list, same as label selectors.
+func formatLabels(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+type ServiceWrap struct {
+	Context   string
+	Namespace string
+	Service   corev1.Service
+}
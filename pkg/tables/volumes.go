@@ -0,0 +1,187 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Volumes struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewVolumes(kc *kubeapi.KubeConfig) *Volumes {
+	return &Volumes{kc: kc}
+}
+
+func (d *Volumes) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
+		table.TextColumn("deployment"),
+		table.TextColumn("name"),
+		table.TextColumn("type"),
+		table.TextColumn("source"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *Volumes) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "volumes")
+	logQueryContext(logger, queryContext)
+
+	selector := labelSelector(queryContext)
+
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]map[string]string, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		workloads, err := listWorkloads(ctx, d.kc, namespaces[i], queryContext, "workload_kind", "deployment")
+		if err != nil {
+			return err
+		}
+
+		for _, w := range workloads {
+			for _, v := range w.Template.Spec.Volumes {
+				typ, source := volumeSource(v.VolumeSource)
+
+				results[i] = append(results[i], map[string]string{
+					"context":        w.Context,
+					"namespace":      w.Namespace,
+					"workload_kind":  w.Kind,
+					"deployment":     w.Name,
+					"name":           v.Name,
+					"type":           typ,
+					"source":         source,
+					"label_selector": selector,
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		rows = append(rows, r...)
+	}
+
+	return rows, nil
+}
+
+type VolumeMounts struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewVolumeMounts(kc *kubeapi.KubeConfig) *VolumeMounts {
+	return &VolumeMounts{kc: kc}
+}
+
+func (d *VolumeMounts) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("workload_kind"),
+		table.TextColumn("deployment"),
+		table.TextColumn("container"),
+		table.TextColumn("container_type"),
+		table.TextColumn("volume"),
+		table.TextColumn("mount_path"),
+		table.TextColumn("read_only"),
+		table.TextColumn("sub_path"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *VolumeMounts) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "volume-mounts")
+	logQueryContext(logger, queryContext)
+
+	selector := labelSelector(queryContext)
+
+	containers, err := listContainers(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, c := range containers {
+		for _, m := range c.Container.VolumeMounts {
+			subPath := m.SubPath
+			if subPath == "" {
+				subPath = m.SubPathExpr
+			}
+
+			rows = append(rows, map[string]string{
+				"context":        c.Context,
+				"namespace":      c.Namespace,
+				"workload_kind":  c.WorkloadKind,
+				"deployment":     c.Workload,
+				"container":      c.Container.Name,
+				"container_type": c.ContainerType,
+				"volume":         m.Name,
+				"mount_path":     m.MountPath,
+				"read_only":      fmt.Sprintf("%t", m.ReadOnly),
+				"sub_path":       subPath,
+				"label_selector": selector,
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+// volumeSource returns volume type and name of referenced object or path,
+// projected volumes list all their sources as "type:name".
+func volumeSource(v corev1.VolumeSource) (string, string) {
+	switch {
+	case v.Secret != nil:
+		return "secret", v.Secret.SecretName
+	case v.ConfigMap != nil:
+		return "configMap", v.ConfigMap.Name
+	case v.PersistentVolumeClaim != nil:
+		return "persistentVolumeClaim", v.PersistentVolumeClaim.ClaimName
+	case v.EmptyDir != nil:
+		return "emptyDir", string(v.EmptyDir.Medium)
+	case v.HostPath != nil:
+		return "hostPath", v.HostPath.Path
+	case v.DownwardAPI != nil:
+		return "downwardAPI", ""
+	case v.CSI != nil:
+		return "csi", v.CSI.Driver
+	case v.NFS != nil:
+		return "nfs", v.NFS.Server + ":" + v.NFS.Path
+	case v.Ephemeral != nil:
+		return "ephemeral", ""
+	case v.Projected != nil:
+		var sources []string
+		for _, s := range v.Projected.Sources {
+			switch {
+			case s.Secret != nil:
+				sources = append(sources, "secret:"+s.Secret.Name)
+			case s.ConfigMap != nil:
+				sources = append(sources, "configMap:"+s.ConfigMap.Name)
+			case s.ServiceAccountToken != nil:
+				sources = append(sources, "serviceAccountToken:"+s.ServiceAccountToken.Audience)
+			case s.DownwardAPI != nil:
+				sources = append(sources, "downwardAPI:")
+			}
+		}
+		return "projected", strings.Join(sources, ",")
+	}
+
+	return "other", ""
+}
@@ -0,0 +1,152 @@
+package tables
+
+import (
+	"context"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+const (
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+	kindDaemonSet   = "DaemonSet"
+	kindReplicaSet  = "ReplicaSet"
+	kindJob         = "Job"
+	kindCronJob     = "CronJob"
+	kindPod         = "Pod"
+)
+
+// WorkloadWrap is an object owning pod template.
+type WorkloadWrap struct {
+	Context   string
+	Namespace string
+	Kind      string
+	Name      string
+	// Template is shared with kubeapi cache and must not be modified.
+	Template *corev1.PodTemplateSpec
+}
+
+// listWorkloads returns workloads of namespace matching constraints on
+// workload kind, name and label selector. ReplicaSets, Jobs and Pods managed by other
+// workloads are skipped, so every pod template is reported once.
+func listWorkloads(ctx context.Context, kc *kubeapi.KubeConfig, n NamespaceWrap, qc table.QueryContext, kindColumn, nameColumn string) ([]WorkloadWrap, error) {
+	selector := labelSelector(qc)
+
+	var out []WorkloadWrap
+
+	add := func(kind string, meta metav1.ObjectMeta, tmpl *corev1.PodTemplateSpec) {
+		if len(filterConstraint([]string{meta.Name}, qc.Constraints[nameColumn])) == 0 {
+			return
+		}
+
+		out = append(out, WorkloadWrap{
+			Context:   n.Context,
+			Namespace: n.Namespace,
+			Kind:      kind,
+			Name:      meta.Name,
+			Template:  tmpl,
+		})
+	}
+
+	wants := func(kind string) bool {
+		return len(filterConstraint([]string{kind}, qc.Constraints[kindColumn])) != 0
+	}
+
+	if wants(kindDeployment) {
+		deployments, err := kc.ListDeployments(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range deployments {
+			add(kindDeployment, deployments[i].ObjectMeta, &deployments[i].Spec.Template)
+		}
+	}
+
+	if wants(kindStatefulSet) {
+		statefulSets, err := kc.ListStatefulSets(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range statefulSets {
+			add(kindStatefulSet, statefulSets[i].ObjectMeta, &statefulSets[i].Spec.Template)
+		}
+	}
+
+	if wants(kindDaemonSet) {
+		daemonSets, err := kc.ListDaemonSets(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range daemonSets {
+			add(kindDaemonSet, daemonSets[i].ObjectMeta, &daemonSets[i].Spec.Template)
+		}
+	}
+
+	if wants(kindReplicaSet) {
+		replicaSets, err := kc.ListReplicaSets(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range replicaSets {
+			if metav1.GetControllerOf(&replicaSets[i]) != nil {
+				continue
+			}
+
+			add(kindReplicaSet, replicaSets[i].ObjectMeta, &replicaSets[i].Spec.Template)
+		}
+	}
+
+	if wants(kindCronJob) {
+		cronJobs, err := kc.ListCronJobs(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range cronJobs {
+			add(kindCronJob, cronJobs[i].ObjectMeta, &cronJobs[i].Spec.JobTemplate.Spec.Template)
+		}
+	}
+
+	if wants(kindJob) {
+		jobs, err := kc.ListJobs(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range jobs {
+			if metav1.GetControllerOf(&jobs[i]) != nil {
+				continue
+			}
+
+			add(kindJob, jobs[i].ObjectMeta, &jobs[i].Spec.Template)
+		}
+	}
+
+	if wants(kindPod) {
+		pods, err := kc.ListPods(ctx, n.Context, n.Namespace, selector)
+		if err := skipFailed(ctx, kc, err); err != nil {
+			return nil, err
+		}
+
+		for i := range pods {
+			if metav1.GetControllerOf(&pods[i]) != nil {
+				continue
+			}
+
+			add(kindPod, pods[i].ObjectMeta, &corev1.PodTemplateSpec{
+				ObjectMeta: pods[i].ObjectMeta,
+				Spec:       pods[i].Spec,
+			})
+		}
+	}
+
+	return out, nil
+}
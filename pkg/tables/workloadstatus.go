@@ -0,0 +1,233 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/kolide/osquery-go/plugin/table"
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/palestamp/ksql/pkg/kubeapi"
+)
+
+type Workloads struct {
+	kc *kubeapi.KubeConfig
+}
+
+func NewWorkloads(kc *kubeapi.KubeConfig) *Workloads {
+	return &Workloads{kc: kc}
+}
+
+func (d *Workloads) Columns() []table.ColumnDefinition {
+	return []table.ColumnDefinition{
+		table.TextColumn("context"),
+		table.TextColumn("namespace"),
+		table.TextColumn("kind"),
+		table.TextColumn("name"),
+		table.IntegerColumn("desired"),
+		table.IntegerColumn("ready"),
+		table.IntegerColumn("updated"),
+		table.IntegerColumn("available"),
+		table.BigIntColumn("generation"),
+		table.BigIntColumn("observed_generation"),
+		table.TextColumn("strategy"),
+		table.TextColumn("paused"),
+		table.TextColumn("progressing_reason"),
+		table.TextColumn("available_reason"),
+		table.TextColumn("rolled_out"),
+		table.TextColumn("label_selector"),
+	}
+}
+
+func (d *Workloads) Generate(ctx context.Context, queryContext table.QueryContext) ([]map[string]string, error) {
+	logger := log.WithField("generate", "workloads")
+	logQueryContext(logger, queryContext)
+
+	namespaces, err := listNamespaces(ctx, d.kc, queryContext)
+	if err != nil {
+		return nil, err
+	}
+
+	wants := func(kind string) bool {
+		return len(filterConstraint([]string{kind}, queryContext.Constraints["kind"])) != 0
+	}
+
+	match := func(name string) bool {
+		return len(filterConstraint([]string{name}, queryContext.Constraints["name"])) != 0
+	}
+
+	selector := labelSelector(queryContext)
+
+	results := make([][]WorkloadStatus, len(namespaces))
+	err = forEach(d.kc.Concurrency(), len(namespaces), func(i int) error {
+		n := namespaces[i]
+
+		if wants(kindDeployment) {
+			deployments, err := d.kc.ListDeployments(ctx, n.Context, n.Namespace, selector)
+			if err := skipFailed(ctx, d.kc, err); err != nil {
+				return err
+			}
+
+			for _, w := range deployments {
+				if match(w.Name) {
+					results[i] = append(results[i], deploymentStatus(n, w))
+				}
+			}
+		}
+
+		if wants(kindStatefulSet) {
+			statefulSets, err := d.kc.ListStatefulSets(ctx, n.Context, n.Namespace, selector)
+			if err := skipFailed(ctx, d.kc, err); err != nil {
+				return err
+			}
+
+			for _, w := range statefulSets {
+				if match(w.Name) {
+					results[i] = append(results[i], statefulSetStatus(n, w))
+				}
+			}
+		}
+
+		if wants(kindDaemonSet) {
+			daemonSets, err := d.kc.ListDaemonSets(ctx, n.Context, n.Namespace, selector)
+			if err := skipFailed(ctx, d.kc, err); err != nil {
+				return err
+			}
+
+			for _, w := range daemonSets {
+				if match(w.Name) {
+					results[i] = append(results[i], daemonSetStatus(n, w))
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]string
+	for _, r := range results {
+		for _, s := range r {
+			rows = append(rows, map[string]string{
+				"context":             s.Context,
+				"namespace":           s.Namespace,
+				"kind":                s.Kind,
+				"name":                s.Name,
+				"desired":             strconv.Itoa(int(s.Desired)),
+				"ready":               strconv.Itoa(int(s.Ready)),
+				"updated":             strconv.Itoa(int(s.Updated)),
+				"available":           strconv.Itoa(int(s.Available)),
+				"generation":          strconv.FormatInt(s.Generation, 10),
+				"observed_generation": strconv.FormatInt(s.ObservedGeneration, 10),
+				"strategy":            s.Strategy,
+				"paused":              fmt.Sprintf("%t", s.Paused),
+				"progressing_reason":  s.ProgressingReason,
+				"available_reason":    s.AvailableReason,
+				"rolled_out":          fmt.Sprintf("%t", s.RolledOut()),
+				"label_selector":      selector,
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+type WorkloadStatus struct {
+	Context            string
+	Namespace          string
+	Kind               string
+	Name               string
+	Desired            int32
+	Ready              int32
+	Updated            int32
+	Available          int32
+	Generation         int64
+	ObservedGeneration int64
+	Strategy           string
+	Paused             bool
+	ProgressingReason  string
+	AvailableReason    string
+}
+
+// RolledOut reports whether controller observed latest spec and all replicas
+// are updated and available, same as "kubectl rollout status" does.
+func (s WorkloadStatus) RolledOut() bool {
+	return s.ObservedGeneration >= s.Generation &&
+		s.Updated == s.Desired &&
+		s.Available == s.Desired
+}
+
+func deploymentStatus(n NamespaceWrap, d appsv1.Deployment) WorkloadStatus {
+	s := WorkloadStatus{
+		Context:            n.Context,
+		Namespace:          n.Namespace,
+		Kind:               kindDeployment,
+		Name:               d.Name,
+		Desired:            replicas(d.Spec.Replicas),
+		Ready:              d.Status.ReadyReplicas,
+		Updated:            d.Status.UpdatedReplicas,
+		Available:          d.Status.AvailableReplicas,
+		Generation:         d.Generation,
+		ObservedGeneration: d.Status.ObservedGeneration,
+		Strategy:           string(d.Spec.Strategy.Type),
+		Paused:             d.Spec.Paused,
+	}
+
+	for _, c := range d.Status.Conditions {
+		switch c.Type {
+		case appsv1.DeploymentProgressing:
+			s.ProgressingReason = c.Reason
+		case appsv1.DeploymentAvailable:
+			s.AvailableReason = c.Reason
+		}
+	}
+
+	return s
+}
+
+// statefulSetStatus reports ready replicas as available, apps/v1 StatefulSet
+// status does not track availability separately.
+func statefulSetStatus(n NamespaceWrap, ss appsv1.StatefulSet) WorkloadStatus {
+	return WorkloadStatus{
+		Context:            n.Context,
+		Namespace:          n.Namespace,
+		Kind:               kindStatefulSet,
+		Name:               ss.Name,
+		Desired:            replicas(ss.Spec.Replicas),
+		Ready:              ss.Status.ReadyReplicas,
+		Updated:            ss.Status.UpdatedReplicas,
+		Available:          ss.Status.ReadyReplicas,
+		Generation:         ss.Generation,
+		ObservedGeneration: ss.Status.ObservedGeneration,
+		Strategy:           string(ss.Spec.UpdateStrategy.Type),
+	}
+}
+
+func daemonSetStatus(n NamespaceWrap, ds appsv1.DaemonSet) WorkloadStatus {
+	return WorkloadStatus{
+		Context:            n.Context,
+		Namespace:          n.Namespace,
+		Kind:               kindDaemonSet,
+		Name:               ds.Name,
+		Desired:            ds.Status.DesiredNumberScheduled,
+		Ready:              ds.Status.NumberReady,
+		Updated:            ds.Status.UpdatedNumberScheduled,
+		Available:          ds.Status.NumberAvailable,
+		Generation:         ds.Generation,
+		ObservedGeneration: ds.Status.ObservedGeneration,
+		Strategy:           string(ds.Spec.UpdateStrategy.Type),
+	}
+}
+
+// replicas defaults to 1 as API server does.
+func replicas(r *int32) int32 {
+	if r == nil {
+		return 1
+	}
+
+	return *r
+}